@@ -11,7 +11,11 @@ import (
 	"github.com/pintoter/warehouse-api/internal/migrations"
 	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
 	"github.com/pintoter/warehouse-api/internal/server"
+	catalogService "github.com/pintoter/warehouse-api/internal/service/catalog"
+	inventoryService "github.com/pintoter/warehouse-api/internal/service/inventory"
 	productService "github.com/pintoter/warehouse-api/internal/service/product"
+	"github.com/pintoter/warehouse-api/internal/service/validation"
+	warehouseService "github.com/pintoter/warehouse-api/internal/service/warehouse"
 	"github.com/pintoter/warehouse-api/internal/transport"
 	"github.com/pintoter/warehouse-api/pkg/database/postgres"
 	"github.com/pintoter/warehouse-api/pkg/logger"
@@ -39,14 +43,25 @@ func Run() {
 	logger.InfoKV(ctx, "PostgreSQL connected", "Stats", db.Stats())
 
 	repository := productRepository.NewRepository(db)
-	txManager := transaction.NewTransactionManager(db)
-	service := productService.NewService(repository, txManager)
-	handler := transport.NewHandler(service)
+	txManager := transaction.NewTransactionManager(db, &cfg.Tx)
+	service := validation.NewProductService(productService.NewService(repository, txManager, &cfg.Reservation))
+	whService := validation.NewWarehouseService(warehouseService.NewService(repository, txManager))
+	catService := validation.NewCatalogService(catalogService.NewService(repository, txManager))
+	invService := validation.NewInventoryService(inventoryService.NewService(repository, txManager))
+	handler := transport.NewHandler(service, whService, catService, invService)
+	grpcServer := server.NewGRPC(transport.NewProductServer(service).Register, &cfg.GRPC)
 	server := server.New(handler, &cfg.HTTP)
+	reaper := productService.NewReaper(repository, txManager, &cfg.Reaper)
 
 	server.Run()
 	logger.InfoKV(ctx, "Starting server")
 
+	grpcServer.Run()
+	logger.InfoKV(ctx, "Starting gRPC server")
+
+	reaper.Run()
+	logger.InfoKV(ctx, "Starting reservation reaper")
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGQUIT, os.Interrupt)
 
@@ -55,11 +70,20 @@ func Run() {
 		logger.InfoKV(ctx, "Starting gracefully shutdown")
 	case err = <-server.Notify():
 		logger.FatalKV(ctx, "Failed starting server", "err", err.Error())
+	case err = <-grpcServer.Notify():
+		logger.FatalKV(ctx, "Failed starting gRPC server", "err", err.Error())
 	}
 
+	// A failed shutdown is only logged, so the rest of the components still stop gracefully
 	if err := server.Shutdown(); err != nil {
-		logger.FatalKV(ctx, "Failed shutdown server", "err", err.Error())
+		logger.ErrorKV(ctx, "Failed shutdown server", "err", err.Error())
 	}
+
+	if err := grpcServer.Shutdown(); err != nil {
+		logger.ErrorKV(ctx, "Failed shutdown gRPC server", "err", err.Error())
+	}
+
+	reaper.Shutdown()
 }
 
 type LogConfig interface {
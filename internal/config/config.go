@@ -8,6 +8,7 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
+	"github.com/pintoter/warehouse-api/internal/service/model"
 	"github.com/spf13/viper"
 )
 
@@ -41,6 +42,20 @@ func (h *HTTP) GetShutdownTimeout() time.Duration {
 	return h.ShutdownTimeout
 }
 
+type GRPC struct {
+	Host            string
+	Port            string
+	ShutdownTimeout time.Duration
+}
+
+func (g *GRPC) GetAddr() string {
+	return fmt.Sprintf("%s:%s", g.Host, g.Port)
+}
+
+func (g *GRPC) GetShutdownTimeout() time.Duration {
+	return g.ShutdownTimeout
+}
+
 type DB struct {
 	User            string
 	Password        string
@@ -74,6 +89,45 @@ func (db *DB) GetConnMaxLifetime() time.Duration {
 	return db.ConnMaxLifetime
 }
 
+type Tx struct {
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+func (t *Tx) GetMaxRetries() int {
+	return t.MaxRetries
+}
+
+func (t *Tx) GetRetryBaseDelay() time.Duration {
+	return t.RetryBaseDelay
+}
+
+func (t *Tx) GetRetryMaxDelay() time.Duration {
+	return t.RetryMaxDelay
+}
+
+type Reaper struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+func (r *Reaper) GetInterval() time.Duration {
+	return r.Interval
+}
+
+func (r *Reaper) GetBatchSize() int {
+	return r.BatchSize
+}
+
+type Reservation struct {
+	DefaultStrategy string
+}
+
+func (r *Reservation) GetDefaultStrategy() string {
+	return r.DefaultStrategy
+}
+
 type Project struct {
 	Name  string
 	Level string
@@ -94,7 +148,11 @@ func (p *Project) GetMode() string {
 
 type Config struct {
 	HTTP
+	GRPC
 	DB
+	Tx
+	Reaper
+	Reservation
 	Project
 }
 
@@ -128,6 +186,11 @@ func New() *Config {
 		if err != nil {
 			log.Fatal("error: get env for db")
 		}
+
+		strategy := model.AllocationStrategyName(config.Reservation.DefaultStrategy)
+		if strategy != "" && !strategy.IsValid() {
+			log.Fatalf("unknown reservation default strategy %q", strategy)
+		}
 	})
 	return config
 }
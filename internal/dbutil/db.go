@@ -1,6 +1,13 @@
 package dbutil
 
-import "context"
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jmoiron/sqlx"
+)
 
 type key string
 
@@ -8,8 +15,90 @@ const (
 	TxKey key = "tx"
 )
 
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+	uniqueViolationCode      = "23505"
+	numericOutOfRangeCode    = "22003"
+)
+
 type Handler func(ctx context.Context) error
 
 type TxManager interface {
-	WithTx(ctx context.Context, fn Handler) error
+	WithTx(ctx context.Context, fn Handler, opts ...TxOption) error
+}
+
+// TxOptions holds per-call settings of a transaction started by TxManager.
+// MaxRetries < 0 means the manager's configured value is used.
+type TxOptions struct {
+	Isolation  sql.IsolationLevel
+	ReadOnly   bool
+	MaxRetries int
+}
+
+type TxOption func(*TxOptions)
+
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.Isolation = level
+	}
+}
+
+func WithReadOnly() TxOption {
+	return func(o *TxOptions) {
+		o.ReadOnly = true
+	}
+}
+
+func WithMaxRetries(n int) TxOption {
+	return func(o *TxOptions) {
+		o.MaxRetries = n
+	}
+}
+
+// GetExecutor returns the transaction stored in ctx by TxManager,
+// falling back to the connection pool when no transaction is running.
+func GetExecutor(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(TxKey).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// InTx reports whether ctx carries a transaction started by TxManager
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(TxKey).(*sqlx.Tx)
+	return ok
+}
+
+// IsRetryable reports whether err is a serialization failure or a deadlock
+// raised by Postgres, i.e. the whole transaction can be safely run again.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == serializationFailureCode || pgErr.Code == deadlockDetectedCode
+}
+
+// IsUniqueViolation reports whether err is a unique constraint violation raised by Postgres
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == uniqueViolationCode
+}
+
+// IsNumericOutOfRange reports whether err is a numeric overflow raised by Postgres
+func IsNumericOutOfRange(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == numericOutOfRangeCode
 }
@@ -3,33 +3,114 @@ package transaction
 import (
 	"context"
 	"database/sql"
+	"math/rand/v2"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pintoter/warehouse-api/internal/dbutil"
+	"github.com/pintoter/warehouse-api/pkg/logger"
 )
 
+// savepointName is shared by nested calls, Postgres resolves the name to the innermost savepoint
+const savepointName = "nested"
+
+type Config interface {
+	GetMaxRetries() int
+	GetRetryBaseDelay() time.Duration
+	GetRetryMaxDelay() time.Duration
+}
+
 type Manager struct {
-	db *sqlx.DB
+	db             *sqlx.DB
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
 }
 
-func NewTransactionManager(db *sqlx.DB) dbutil.TxManager {
-	return &Manager{db: db}
+func NewTransactionManager(db *sqlx.DB, cfg Config) dbutil.TxManager {
+	return &Manager{
+		db:             db,
+		maxRetries:     cfg.GetMaxRetries(),
+		retryBaseDelay: cfg.GetRetryBaseDelay(),
+		retryMaxDelay:  cfg.GetRetryMaxDelay(),
+	}
 }
 
-func (m *Manager) WithTx(ctx context.Context, fn dbutil.Handler) error {
+func (m *Manager) WithTx(ctx context.Context, fn dbutil.Handler, opts ...dbutil.TxOption) error {
+	options := dbutil.TxOptions{
+		Isolation:  sql.LevelSerializable,
+		MaxRetries: -1,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.MaxRetries < 0 {
+		options.MaxRetries = m.maxRetries
+	}
+
 	txOpts := sql.TxOptions{
-		Isolation: sql.LevelSerializable,
+		Isolation: options.Isolation,
+		ReadOnly:  options.ReadOnly,
+	}
+
+	// Nested calls join the outer transaction, which is the one to be retried
+	if tx, ok := ctx.Value(dbutil.TxKey).(*sqlx.Tx); ok {
+		return m.savepoint(ctx, tx, fn)
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = m.transaction(ctx, txOpts, fn)
+		if err == nil || !dbutil.IsRetryable(err) || attempt >= options.MaxRetries {
+			return err
+		}
+
+		logger.DebugKV(ctx, "Transaction", "retry", attempt+1, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(m.backoff(attempt)):
+		}
 	}
-	return m.transaction(ctx, txOpts, fn)
 }
 
-func (m *Manager) transaction(ctx context.Context, txOpts sql.TxOptions, fn dbutil.Handler) (err error) {
-	tx, ok := ctx.Value(dbutil.TxKey).(*sql.Tx)
-	if ok {
-		return fn(ctx)
+// backoff returns an exponential delay for the attempt with a random jitter in [delay/2, delay]
+func (m *Manager) backoff(attempt int) time.Duration {
+	delay := m.retryBaseDelay << attempt
+	if delay <= 0 || (m.retryMaxDelay > 0 && delay > m.retryMaxDelay) {
+		delay = m.retryMaxDelay
+	}
+
+	if delay <= 1 {
+		return delay
 	}
 
-	tx, err = m.db.BeginTx(ctx, &txOpts)
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(delay-half)))
+}
+
+// savepoint runs a nested call, its changes are rolled back on error and the outer transaction goes on
+func (m *Manager) savepoint(ctx context.Context, tx *sqlx.Tx, fn dbutil.Handler) (err error) {
+	if _, err = tx.ExecContext(ctx, "SAVEPOINT "+savepointName); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			// The call may fail because ctx is done, the rollback must still happen
+			_, _ = tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+savepointName)
+			return
+		}
+		_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepointName)
+	}()
+
+	return fn(ctx)
+}
+
+func (m *Manager) transaction(ctx context.Context, txOpts sql.TxOptions, fn dbutil.Handler) (err error) {
+	tx, err := m.db.BeginTxx(ctx, &txOpts)
 	if err != nil {
 		return err
 	}
@@ -37,14 +118,17 @@ func (m *Manager) transaction(ctx context.Context, txOpts sql.TxOptions, fn dbut
 	ctx = context.WithValue(ctx, dbutil.TxKey, tx)
 
 	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+
 		if err != nil {
 			_ = tx.Rollback()
 			return
 		}
 
-		if nil == err {
-			_ = tx.Commit()
-		}
+		err = tx.Commit()
 	}()
 
 	return fn(ctx)
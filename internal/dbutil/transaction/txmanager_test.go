@@ -0,0 +1,212 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type config struct {
+	maxRetries int
+}
+
+func (c config) GetMaxRetries() int {
+	return c.maxRetries
+}
+
+func (c config) GetRetryBaseDelay() time.Duration {
+	return time.Millisecond
+}
+
+func (c config) GetRetryMaxDelay() time.Duration {
+	return 5 * time.Millisecond
+}
+
+func TestWithTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	m := NewTransactionManager(sqlxDB, config{maxRetries: 2})
+
+	expectedQuery := "UPDATE warehouse_product SET quantity = $1"
+
+	exec := func(ctx context.Context) error {
+		_, err := dbutil.GetExecutor(ctx, sqlxDB).ExecContext(ctx, expectedQuery, 1)
+		return err
+	}
+
+	type mockBehavior func()
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		fn           dbutil.Handler
+		opts         []dbutil.TxOption
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("SAVEPOINT nested").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("RELEASE SAVEPOINT nested").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				if err := exec(ctx); err != nil {
+					return err
+				}
+
+				return m.WithTx(ctx, exec)
+			},
+		},
+		{
+			name: "Nested call rolled back to savepoint",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT nested").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT nested").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				err := m.WithTx(ctx, func(ctx context.Context) error {
+					if err := exec(ctx); err != nil {
+						return err
+					}
+
+					return errors.New("any error")
+				})
+				if err == nil {
+					return errors.New("nested error is lost")
+				}
+
+				return exec(ctx)
+			},
+		},
+		{
+			name: "Rollback",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context) error {
+				if err := exec(ctx); err != nil {
+					return err
+				}
+
+				return errors.New("any error")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Failed commit",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit().WillReturnError(errors.New("any error"))
+			},
+			fn:      exec,
+			wantErr: true,
+		},
+		{
+			name: "Retry on serialization failure",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnError(&pgconn.PgError{Code: "40001"})
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit().WillReturnError(&pgconn.PgError{Code: "40P01"})
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: exec,
+		},
+		{
+			name: "Retries exhausted",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnError(&pgconn.PgError{Code: "40001"})
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnError(&pgconn.PgError{Code: "40001"})
+				mock.ExpectRollback()
+			},
+			fn:      exec,
+			opts:    []dbutil.TxOption{dbutil.WithMaxRetries(1)},
+			wantErr: true,
+		},
+		{
+			name: "No retry on other errors",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1).
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+				mock.ExpectRollback()
+			},
+			fn:      exec,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			err := m.WithTx(context.Background(), tt.fn, tt.opts...)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
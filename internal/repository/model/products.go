@@ -1,14 +1,76 @@
 package model
 
+import (
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
 type ProductsOnActiveWarehouse struct {
 	WarehouseId int
 	ProductId   int
 	Quantity    int
+	Priority    int
+	Region      string
+	Location    *model.GeoPoint
 }
 
 type ProductsInReservation struct {
-	ID          int
+	ID            int
+	ReservationId string
+	WarehouseId   int
+	ProductId     int
+	Code          string
+	Quantity      int
+}
+
+type IdempotencyKey struct {
+	Method      string
+	Key         string
+	RequestHash string
+	Response    []byte
+}
+
+type ProductFilter struct {
+	Name   string
+	Size   string
+	Limit  int
+	Offset int
+}
+
+type Transfer struct {
+	ID              int
+	ProductId       int
+	Code            string
+	FromWarehouseId int
+	ToWarehouseId   int
+	Quantity        int
+	Status          model.TransferStatus
+	ShippedAt       time.Time
+	ReceivedAt      *time.Time
+}
+
+type StockMovement struct {
 	WarehouseId int
 	ProductId   int
-	Quantity    int
+	Delta       int
+	Reason      model.MovementReason
+	ReferenceId string
+}
+
+type StockMovementFilter struct {
+	Code        string
+	WarehouseId int
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+	Offset      int
+}
+
+type StockAdjustment struct {
+	WarehouseId     int
+	ProductId       int
+	Reason          model.AdjustmentReason
+	CountedQuantity *int
+	Delta           int
 }
@@ -0,0 +1,33 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+)
+
+func createStockAdjustmentBuilder(adjustment repoModel.StockAdjustment) (string, []interface{}, error) {
+	builder := sq.Insert(stockAdjustment).
+		Columns("warehouse_id", "product_id", "reason", "counted_quantity", "delta").
+		Values(adjustment.WarehouseId, adjustment.ProductId, adjustment.Reason, adjustment.CountedQuantity, adjustment.Delta).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) CreateStockAdjustment(ctx context.Context, adjustment repoModel.StockAdjustment) (int, error) {
+	query, args, err := createStockAdjustmentBuilder(adjustment)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
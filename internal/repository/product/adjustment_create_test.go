@@ -0,0 +1,78 @@
+package product
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStockAdjustment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		adjustment repoModel.StockAdjustment
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "INSERT INTO stock_adjustment (warehouse_id,product_id,reason,counted_quantity,delta) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+	counted := 2
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantId       int
+	}{
+		{
+			name: "Count",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(1, 1, model.AdjustmentCount, 2, -1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			args: args{
+				adjustment: repoModel.StockAdjustment{WarehouseId: 1, ProductId: 1, Reason: model.AdjustmentCount, CountedQuantity: &counted, Delta: -1},
+			},
+			wantId: 1,
+		},
+		{
+			name: "Delta",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(2, 5, model.AdjustmentDamaged, nil, -2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			},
+			args: args{
+				adjustment: repoModel.StockAdjustment{WarehouseId: 2, ProductId: 5, Reason: model.AdjustmentDamaged, Delta: -2},
+			},
+			wantId: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotId, err := r.CreateStockAdjustment(context.Background(), tt.args.adjustment)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantId, gotId)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func createIdempotencyKeyBuilder(method, key, requestHash string) (string, []interface{}, error) {
+	builder := sq.Insert(idempotencyKey).
+		Columns("method", "key", "request_hash").
+		Values(method, key, requestHash).
+		Suffix("ON CONFLICT (method, key) DO NOTHING").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// CreateIdempotencyKey claims the key for the method. It returns false if the key is already claimed.
+func (r *repo) CreateIdempotencyKey(ctx context.Context, method, key, requestHash string) (bool, error) {
+	query, args, err := createIdempotencyKeyBuilder(method, key, requestHash)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.getExecutor(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return created == 1, nil
+}
@@ -0,0 +1,86 @@
+package product
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		method      string
+		key         string
+		requestHash string
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "INSERT INTO idempotency_key (method,key,request_hash) VALUES ($1,$2,$3) ON CONFLICT (method, key) DO NOTHING"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantCreated  bool
+		wantErr      bool
+	}{
+		{
+			name: "Created",
+			mockBehavior: func(args args) {
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.method, args.key, args.requestHash).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			args: args{
+				method:      "ProductService.ReserveProducts",
+				key:         "order-1",
+				requestHash: "hash",
+			},
+			wantCreated: true,
+		},
+		{
+			name: "Already exists",
+			mockBehavior: func(args args) {
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.method, args.key, args.requestHash).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			args: args{
+				method:      "ProductService.ReserveProducts",
+				key:         "order-1",
+				requestHash: "hash",
+			},
+			wantCreated: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotCreated, err := r.CreateIdempotencyKey(context.Background(), tt.args.method, tt.args.key, tt.args.requestHash)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantCreated, gotCreated)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
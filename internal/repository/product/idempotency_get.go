@@ -0,0 +1,33 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+)
+
+func getIdempotencyKeyBuilder(method, key string) (string, []interface{}, error) {
+	builder := sq.Select("method", "key", "request_hash", "response").
+		From(idempotencyKey).
+		Where(sq.Eq{"method": method, "key": key}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetIdempotencyKey(ctx context.Context, method, key string) (repoModel.IdempotencyKey, error) {
+	query, args, err := getIdempotencyKeyBuilder(method, key)
+	if err != nil {
+		return repoModel.IdempotencyKey{}, err
+	}
+
+	var idempotencyKey repoModel.IdempotencyKey
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).
+		Scan(&idempotencyKey.Method, &idempotencyKey.Key, &idempotencyKey.RequestHash, &idempotencyKey.Response)
+	if err != nil {
+		return repoModel.IdempotencyKey{}, err
+	}
+
+	return idempotencyKey, nil
+}
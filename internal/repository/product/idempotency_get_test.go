@@ -0,0 +1,91 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIdempotencyKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		method string
+		key    string
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "SELECT method, key, request_hash, response FROM idempotency_key WHERE key = $1 AND method = $2"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantKey      repoModel.IdempotencyKey
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.key, args.method).
+					WillReturnRows(sqlmock.NewRows([]string{"method", "key", "request_hash", "response"}).
+						AddRow(args.method, args.key, "hash", []byte(`{"reservation_id":"1"}`)))
+			},
+			args: args{
+				method: "ProductService.ReserveProducts",
+				key:    "order-1",
+			},
+			wantKey: repoModel.IdempotencyKey{
+				Method:      "ProductService.ReserveProducts",
+				Key:         "order-1",
+				RequestHash: "hash",
+				Response:    []byte(`{"reservation_id":"1"}`),
+			},
+		},
+		{
+			name: "Not found",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.key, args.method).
+					WillReturnError(sql.ErrNoRows)
+			},
+			args: args{
+				method: "ProductService.ReserveProducts",
+				key:    "order-2",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotKey, err := r.GetIdempotencyKey(context.Background(), tt.args.method, tt.args.key)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantKey, gotKey)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
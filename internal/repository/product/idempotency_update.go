@@ -0,0 +1,30 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func updateIdempotencyResponseBuilder(method, key string, response []byte) (string, []interface{}, error) {
+	builder := sq.Update(idempotencyKey).
+		Where(sq.Eq{"method": method, "key": key}).
+		Set("response", response).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) UpdateIdempotencyResponse(ctx context.Context, method, key string, response []byte) error {
+	query, args, err := updateIdempotencyResponseBuilder(method, key, response)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.getExecutor(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+func createProductBuilder(p model.CatalogProduct) (string, []interface{}, error) {
+	builder := sq.Insert(product).
+		Columns("name", "size", "code").
+		Values(p.Name, p.Size, p.Code).
+		Suffix("RETURNING id, name, size, code").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) CreateProduct(ctx context.Context, p model.CatalogProduct) (model.CatalogProduct, error) {
+	query, args, err := createProductBuilder(p)
+	if err != nil {
+		return model.CatalogProduct{}, err
+	}
+
+	var created model.CatalogProduct
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&created.ID, &created.Name, &created.Size, &created.Code)
+	if err != nil {
+		return model.CatalogProduct{}, err
+	}
+
+	return created, nil
+}
@@ -0,0 +1,83 @@
+package product
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProduct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		product model.CatalogProduct
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "INSERT INTO product (name,size,code) VALUES ($1,$2,$3) RETURNING id, name, size, code"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantProduct  model.CatalogProduct
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.product.Name, args.product.Size, args.product.Code).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}).
+						AddRow(16, args.product.Name, args.product.Size, args.product.Code))
+			},
+			args: args{
+				product: model.CatalogProduct{Name: "Puma Cap", Size: "M", Code: "777"},
+			},
+			wantProduct: model.CatalogProduct{ID: 16, Name: "Puma Cap", Size: "M", Code: "777"},
+		},
+		{
+			name: "Duplicate code",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.product.Name, args.product.Size, args.product.Code).
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+			},
+			args: args{
+				product: model.CatalogProduct{Name: "Lacoste T-Shirt", Size: "XS", Code: "12345"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotProduct, err := r.CreateProduct(context.Background(), tt.args.product)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantProduct, gotProduct)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package product
+
+import (
+	"context"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+func getProductByCodeBuilder(code string) (string, []interface{}, error) {
+	builder := sq.Select("id", "name", "size", "code").
+		From(product).
+		Where(sq.Eq{"code": code}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetProductByCode(ctx context.Context, code string) (model.CatalogProduct, error) {
+	query, args, err := getProductByCodeBuilder(code)
+	if err != nil {
+		return model.CatalogProduct{}, err
+	}
+
+	var p model.CatalogProduct
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&p.ID, &p.Name, &p.Size, &p.Code)
+	if err != nil {
+		return model.CatalogProduct{}, err
+	}
+
+	return p, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func productFilterWhere(filter repoModel.ProductFilter) sq.And {
+	where := sq.And{}
+	if filter.Name != "" {
+		where = append(where, sq.ILike{"name": "%" + likeEscaper.Replace(filter.Name) + "%"})
+	}
+	if filter.Size != "" {
+		where = append(where, sq.Eq{"size": filter.Size})
+	}
+
+	return where
+}
+
+func getProductsBuilder(filter repoModel.ProductFilter) (string, []interface{}, error) {
+	builder := sq.Select("id", "name", "size", "code").
+		From(product).
+		Where(productFilterWhere(filter)).
+		OrderBy("id").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetProducts(ctx context.Context, filter repoModel.ProductFilter) ([]model.CatalogProduct, error) {
+	query, args, err := getProductsBuilder(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var products []model.CatalogProduct
+	for rows.Next() {
+		var p model.CatalogProduct
+
+		err = rows.Scan(&p.ID, &p.Name, &p.Size, &p.Code)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, p)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return products, nil
+}
+
+func getProductsCountBuilder(filter repoModel.ProductFilter) (string, []interface{}, error) {
+	builder := sq.Select("COUNT(*)").
+		From(product).
+		Where(productFilterWhere(filter)).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetProductsCount(ctx context.Context, filter repoModel.ProductFilter) (int, error) {
+	query, args, err := getProductsCountBuilder(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
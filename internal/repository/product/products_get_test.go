@@ -0,0 +1,156 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProductByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		code string
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "SELECT id, name, size, code FROM product WHERE code = $1"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantProduct  model.CatalogProduct
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}).
+						AddRow(1, "Lacoste T-Shirt", "XS", args.code))
+			},
+			args:        args{code: "12345"},
+			wantProduct: model.CatalogProduct{ID: 1, Name: "Lacoste T-Shirt", Size: "XS", Code: "12345"},
+		},
+		{
+			name: "Not found",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}))
+			},
+			args:    args{code: "00000"},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotProduct, err := r.GetProductByCode(context.Background(), tt.args.code)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantProduct, gotProduct)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		filter repoModel.ProductFilter
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantProducts []model.CatalogProduct
+		wantCount    int
+	}{
+		{
+			name: "Without filters",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, size, code FROM product WHERE (1=1) ORDER BY id LIMIT 2 OFFSET 0")).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}).
+						AddRow(1, "Lacoste T-Shirt", "XS", "12345").
+						AddRow(2, "Lacoste T-Shirt", "S", "12346"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM product WHERE (1=1)")).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(15))
+			},
+			args: args{
+				filter: repoModel.ProductFilter{Limit: 2},
+			},
+			wantProducts: []model.CatalogProduct{
+				{ID: 1, Name: "Lacoste T-Shirt", Size: "XS", Code: "12345"},
+				{ID: 2, Name: "Lacoste T-Shirt", Size: "S", Code: "12346"},
+			},
+			wantCount: 15,
+		},
+		{
+			name: "With name and size",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, size, code FROM product WHERE (name ILIKE $1 AND size = $2) ORDER BY id LIMIT 10 OFFSET 10")).
+					WithArgs("%100\\%%", "L").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM product WHERE (name ILIKE $1 AND size = $2)")).
+					WithArgs("%100\\%%", "L").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			args: args{
+				filter: repoModel.ProductFilter{Name: "100%", Size: "L", Limit: 10, Offset: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotProducts, err := r.GetProducts(context.Background(), tt.args.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantProducts, gotProducts)
+
+			gotCount, err := r.GetProductsCount(context.Background(), tt.args.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCount, gotCount)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+func updateProductBuilder(p model.CatalogProduct) (string, []interface{}, error) {
+	builder := sq.Update(product).
+		Set("name", p.Name).
+		Set("size", p.Size).
+		Set("code", p.Code).
+		Where(sq.Eq{"id": p.ID}).
+		Suffix("RETURNING id, name, size, code").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) UpdateProduct(ctx context.Context, p model.CatalogProduct) (model.CatalogProduct, error) {
+	query, args, err := updateProductBuilder(p)
+	if err != nil {
+		return model.CatalogProduct{}, err
+	}
+
+	var updated model.CatalogProduct
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&updated.ID, &updated.Name, &updated.Size, &updated.Code)
+	if err != nil {
+		return model.CatalogProduct{}, err
+	}
+
+	return updated, nil
+}
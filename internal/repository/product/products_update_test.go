@@ -0,0 +1,83 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateProduct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		product model.CatalogProduct
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "UPDATE product SET name = $1, size = $2, code = $3 WHERE id = $4 RETURNING id, name, size, code"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantProduct  model.CatalogProduct
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.product.Name, args.product.Size, args.product.Code, args.product.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}).
+						AddRow(args.product.ID, args.product.Name, args.product.Size, args.product.Code))
+			},
+			args: args{
+				product: model.CatalogProduct{ID: 6, Name: "Moms pants", Size: "L", Code: "1337"},
+			},
+			wantProduct: model.CatalogProduct{ID: 6, Name: "Moms pants", Size: "L", Code: "1337"},
+		},
+		{
+			name: "Not found",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.product.Name, args.product.Size, args.product.Code, args.product.ID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}))
+			},
+			args: args{
+				product: model.CatalogProduct{ID: 100, Name: "Nothing", Size: "S", Code: "0"},
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotProduct, err := r.UpdateProduct(context.Background(), tt.args.product)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantProduct, gotProduct)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
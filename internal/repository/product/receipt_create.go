@@ -0,0 +1,59 @@
+package product
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func createReceiptBuilder(supplierRef string, warehouseId int) (string, []interface{}, error) {
+	builder := sq.Insert(receipt).
+		Columns("supplier_ref", "warehouse_id").
+		Values(supplierRef, warehouseId).
+		Suffix("RETURNING id, received_at").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) CreateReceipt(ctx context.Context, supplierRef string, warehouseId int) (int, time.Time, error) {
+	query, args, err := createReceiptBuilder(supplierRef, warehouseId)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var (
+		id         int
+		receivedAt time.Time
+	)
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&id, &receivedAt)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return id, receivedAt, nil
+}
+
+func createReceiptLineBuilder(receiptId, productId, quantity int) (string, []interface{}, error) {
+	builder := sq.Insert(receiptLine).
+		Columns("receipt_id", "product_id", "quantity").
+		Values(receiptId, productId, quantity).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) CreateReceiptLine(ctx context.Context, receiptId, productId, quantity int) error {
+	query, args, err := createReceiptLineBuilder(receiptId, productId, quantity)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.getExecutor(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package product
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateReceipt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		supplierRef string
+		warehouseId int
+	}
+
+	type mockBehavior func(args args)
+
+	receivedAt := time.Date(2024, 3, 25, 10, 0, 0, 0, time.UTC)
+	expectedQuery := "INSERT INTO receipt (supplier_ref,warehouse_id) VALUES ($1,$2) RETURNING id, received_at"
+
+	tests := []struct {
+		name           string
+		mockBehavior   mockBehavior
+		args           args
+		wantId         int
+		wantReceivedAt time.Time
+		wantErr        bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.supplierRef, args.warehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "received_at"}).AddRow(1, receivedAt))
+			},
+			args: args{
+				supplierRef: "INV-2024-001",
+				warehouseId: 1,
+			},
+			wantId:         1,
+			wantReceivedAt: receivedAt,
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.supplierRef, args.warehouseId).
+					WillReturnError(errors.New("some error"))
+			},
+			args: args{
+				supplierRef: "INV-2024-001",
+				warehouseId: 100,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotId, gotReceivedAt, err := r.CreateReceipt(context.Background(), tt.args.supplierRef, tt.args.warehouseId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantId, gotId)
+				assert.Equal(t, tt.wantReceivedAt, gotReceivedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateReceiptLine(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO receipt_line (receipt_id,product_id,quantity) VALUES ($1,$2,$3)")).
+		WithArgs(1, 5, 10).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = r.CreateReceiptLine(context.Background(), 1, 5, 10)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,7 +1,10 @@
 package product
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil"
 	"github.com/pintoter/warehouse-api/internal/repository"
 )
 
@@ -10,6 +13,12 @@ const (
 	warehouse        = "warehouse"
 	warehouseProduct = "warehouse_product"
 	reservation      = "reservation"
+	idempotencyKey   = "idempotency_key"
+	receipt          = "receipt"
+	receiptLine      = "receipt_line"
+	transfer         = "transfer"
+	stockMovement    = "stock_movement"
+	stockAdjustment  = "stock_adjustment"
 )
 
 type repo struct {
@@ -21,3 +30,7 @@ func NewRepository(db *sqlx.DB) repository.Repository {
 		db: db,
 	}
 }
+
+func (r *repo) getExecutor(ctx context.Context) sqlx.ExtContext {
+	return dbutil.GetExecutor(ctx, r.db)
+}
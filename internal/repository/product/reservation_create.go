@@ -6,24 +6,32 @@ import (
 	sq "github.com/Masterminds/squirrel"
 )
 
-func createReservationBuilder(warehouseId, productId, quantity int, reservationId string) (string, []interface{}, error) {
+func createReservationBuilder(warehouseId, productId, quantity int, reservationId string, ttlSeconds int) (string, []interface{}, error) {
+	columns := []string{"reservation_id", "warehouse_id", "product_id", "quantity"}
+	values := []interface{}{reservationId, warehouseId, productId, quantity}
+
+	if ttlSeconds > 0 {
+		columns = append(columns, "expires_at")
+		values = append(values, sq.Expr("CURRENT_TIMESTAMP + make_interval(secs => ?)", ttlSeconds))
+	}
+
 	builder := sq.Insert(reservation).
-		Columns("reservation_id", "warehouse_id", "product_id", "quantity").
-		Values(reservationId, warehouseId, productId, quantity).
+		Columns(columns...).
+		Values(values...).
 		Suffix("RETURNING id").
 		PlaceholderFormat(sq.Dollar)
 
 	return builder.ToSql()
 }
 
-func (r *repo) CreateReservation(ctx context.Context, warehouseId, productId, quantity int, reservationId string) (int, error) {
-	query, args, err := createReservationBuilder(warehouseId, productId, quantity, reservationId)
+func (r *repo) CreateReservation(ctx context.Context, warehouseId, productId, quantity int, reservationId string, ttlSeconds int) (int, error) {
+	query, args, err := createReservationBuilder(warehouseId, productId, quantity, reservationId, ttlSeconds)
 	if err != nil {
 		return 0, err
 	}
 
 	var id int
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&id)
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -27,6 +27,7 @@ func TestCreateReservation(t *testing.T) {
 		productId     int
 		quantity      int
 		reservationId string
+		ttlSeconds    int
 	}
 
 	type mockBehavior func(args args)
@@ -58,13 +59,35 @@ func TestCreateReservation(t *testing.T) {
 			},
 			wantId: 1,
 		},
+		{
+			name: "Success with ttl",
+			mockBehavior: func(args args) {
+				expectedQueryInReservation := "INSERT INTO reservation (reservation_id,warehouse_id,product_id,quantity,expires_at) VALUES ($1,$2,$3,$4,CURRENT_TIMESTAMP + make_interval(secs => $5)) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQueryInReservation)).
+					WithArgs(
+						args.reservationId,
+						args.warehouseId,
+						args.productId,
+						args.quantity,
+						args.ttlSeconds,
+					).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			},
+			args: args{
+				warehouseId:   1,
+				productId:     1,
+				quantity:      2,
+				reservationId: "1337",
+				ttlSeconds:    900,
+			},
+			wantId: 2,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
 
-			gotId, err := r.CreateReservation(context.Background(), tt.args.warehouseId, tt.args.productId, tt.args.quantity, tt.args.reservationId)
+			gotId, err := r.CreateReservation(context.Background(), tt.args.warehouseId, tt.args.productId, tt.args.quantity, tt.args.reservationId, tt.args.ttlSeconds)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
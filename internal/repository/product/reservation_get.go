@@ -4,7 +4,9 @@ import (
 	"context"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
 	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
 	"github.com/pkg/errors"
 )
 
@@ -12,7 +14,7 @@ func getTotalQuantityOfReservationBuilder(reservationId, productCode string) (st
 	builder := sq.Select("SUM(r.quantity)").
 		From(reservation + " r").
 		Join(product + " p ON p.id = r.product_id").
-		Where(sq.Eq{"r.reservation_id": reservationId, "p.code": productCode}).
+		Where(sq.Eq{"r.reservation_id": reservationId, "p.code": productCode, "r.status": model.ReservationReserved}).
 		PlaceholderFormat(sq.Dollar)
 
 	return builder.ToSql()
@@ -25,7 +27,7 @@ func (r *repo) GetTotalQuantityOfReservation(ctx context.Context, reservationId
 	}
 
 	var totalQuantity int
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&totalQuantity)
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&totalQuantity)
 	if err != nil {
 		return 0, err
 	}
@@ -34,11 +36,11 @@ func (r *repo) GetTotalQuantityOfReservation(ctx context.Context, reservationId
 }
 
 func getProductsByReservationByCodeBuilder(reservationId, code string) (string, []interface{}, error) {
-	builder := sq.Select("r.id, r.warehouse_id, r.product_id, r.quantity").
+	builder := sq.Select("r.id, r.reservation_id, r.warehouse_id, r.product_id, r.quantity").
 		From(reservation + " r").
 		Join(product + " p ON p.id = r.product_id").
 		Join(warehouse + " w ON w.id = r.warehouse_id").
-		Where(sq.Eq{"p.code": code, "r.reservation_id": reservationId}).
+		Where(sq.Eq{"p.code": code, "r.reservation_id": reservationId, "r.status": model.ReservationReserved}).
 		OrderBy("r.quantity DESC").
 		PlaceholderFormat(sq.Dollar)
 
@@ -51,7 +53,7 @@ func (r *repo) GetProductsByReservationByIdAndCode(ctx context.Context, reservat
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +65,7 @@ func (r *repo) GetProductsByReservationByIdAndCode(ctx context.Context, reservat
 	for rows.Next() {
 		var ProductInReservation repoModel.ProductsInReservation
 
-		err = rows.Scan(&ProductInReservation.ID, &ProductInReservation.WarehouseId, &ProductInReservation.ProductId, &ProductInReservation.Quantity)
+		err = rows.Scan(&ProductInReservation.ID, &ProductInReservation.ReservationId, &ProductInReservation.WarehouseId, &ProductInReservation.ProductId, &ProductInReservation.Quantity)
 		if err != nil {
 			return nil, errors.Wrap(err, "GetProductsInReservation.rows.Scan")
 		}
@@ -77,3 +79,191 @@ func (r *repo) GetProductsByReservationByIdAndCode(ctx context.Context, reservat
 
 	return productsInReservation, nil
 }
+
+func getProductsByReservationIdBuilder(reservationId string) (string, []interface{}, error) {
+	builder := sq.Select("r.id, r.reservation_id, r.warehouse_id, r.product_id, p.code, r.quantity").
+		From(reservation+" r").
+		Join(product+" p ON p.id = r.product_id").
+		Where(sq.Eq{"r.reservation_id": reservationId, "r.status": model.ReservationReserved}).
+		OrderBy("p.code", "r.warehouse_id").
+		Suffix("FOR UPDATE OF r").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetProductsByReservationId(ctx context.Context, reservationId string) ([]repoModel.ProductsInReservation, error) {
+	query, args, err := getProductsByReservationIdBuilder(reservationId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var productsInReservation []repoModel.ProductsInReservation
+	for rows.Next() {
+		var productInReservation repoModel.ProductsInReservation
+
+		err = rows.Scan(&productInReservation.ID, &productInReservation.ReservationId, &productInReservation.WarehouseId, &productInReservation.ProductId, &productInReservation.Code, &productInReservation.Quantity)
+		if err != nil {
+			return nil, errors.Wrap(err, "GetProductsByReservationId.rows.Scan")
+		}
+
+		productsInReservation = append(productsInReservation, productInReservation)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return productsInReservation, nil
+}
+
+func getExpiredReservationsBuilder(limit int) (string, []interface{}, error) {
+	builder := sq.Select("id, reservation_id, warehouse_id, product_id, quantity").
+		From(reservation).
+		Where(sq.And{
+			sq.Expr("expires_at <= CURRENT_TIMESTAMP"),
+			sq.Eq{"status": model.ReservationReserved},
+		}).
+		OrderBy("expires_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetExpiredReservations(ctx context.Context, limit int) ([]repoModel.ProductsInReservation, error) {
+	query, args, err := getExpiredReservationsBuilder(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var expiredReservations []repoModel.ProductsInReservation
+	for rows.Next() {
+		var expiredReservation repoModel.ProductsInReservation
+
+		err = rows.Scan(&expiredReservation.ID, &expiredReservation.ReservationId, &expiredReservation.WarehouseId, &expiredReservation.ProductId, &expiredReservation.Quantity)
+		if err != nil {
+			return nil, errors.Wrap(err, "GetExpiredReservations.rows.Scan")
+		}
+
+		expiredReservations = append(expiredReservations, expiredReservation)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return expiredReservations, nil
+}
+
+func getReservationStatusesBuilder(reservationId string) (string, []interface{}, error) {
+	builder := sq.Select("status").
+		From(reservation).
+		Where(sq.Eq{"reservation_id": reservationId}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetReservationStatuses(ctx context.Context, reservationId string) ([]model.ReservationStatus, error) {
+	query, args, err := getReservationStatusesBuilder(reservationId)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []model.ReservationStatus
+	err = sqlx.SelectContext(ctx, r.getExecutor(ctx), &statuses, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+func getReservationByIdBuilder(reservationId string) (string, []interface{}, error) {
+	builder := sq.Select("p.code", "p.name", "p.size", "w.id", "w.name", "r.quantity", "r.reserved_at", "r.status").
+		From(reservation+" r").
+		Join(product+" p ON p.id = r.product_id").
+		Join(warehouse+" w ON w.id = r.warehouse_id").
+		Where(sq.Eq{"r.reservation_id": reservationId}).
+		OrderBy("p.code", "w.id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetReservationById(ctx context.Context, reservationId string) ([]model.ReservationLine, error) {
+	query, args, err := getReservationByIdBuilder(reservationId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var lines []model.ReservationLine
+	for rows.Next() {
+		var line model.ReservationLine
+
+		err = rows.Scan(&line.Code, &line.Name, &line.Size, &line.WarehouseId, &line.WarehouseName, &line.Quantity, &line.ReservedAt, &line.Status)
+		if err != nil {
+			return nil, errors.Wrap(err, "GetReservationById.rows.Scan")
+		}
+
+		lines = append(lines, line)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return lines, nil
+}
+
+func getReservedQuantityBuilder(warehouseId, productId int) (string, []interface{}, error) {
+	builder := sq.Select("COALESCE(SUM(quantity), 0)").
+		From(reservation).
+		Where(sq.Eq{"warehouse_id": warehouseId, "product_id": productId, "status": model.ReservationReserved}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetReservedQuantity returns the quantity of the product held in the warehouse by active reservations
+func (r *repo) GetReservedQuantity(ctx context.Context, warehouseId, productId int) (int, error) {
+	query, args, err := getReservedQuantityBuilder(warehouseId, productId)
+	if err != nil {
+		return 0, err
+	}
+
+	var reserved int
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&reserved)
+	if err != nil {
+		return 0, err
+	}
+
+	return reserved, nil
+}
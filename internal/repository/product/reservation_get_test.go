@@ -2,13 +2,16 @@ package product
 
 import (
 	"context"
+	"errors"
 	"log"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,11 +43,12 @@ func TestGetTotalQuantityOfReservation(t *testing.T) {
 		{
 			name: "Success",
 			mockBehavior: func(args args) {
-				expectedExecInReservation := "SELECT SUM(r.quantity) FROM reservation r JOIN product p ON p.id = r.product_id WHERE p.code = $1 AND r.reservation_id = $2"
+				expectedExecInReservation := "SELECT SUM(r.quantity) FROM reservation r JOIN product p ON p.id = r.product_id WHERE p.code = $1 AND r.reservation_id = $2 AND r.status = $3"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedExecInReservation)).
 					WithArgs(
 						args.productCode,
 						args.reservationId,
+						model.ReservationReserved,
 					).WillReturnRows(sqlmock.NewRows([]string{"SUM(quantity)"}).AddRow(50))
 			},
 			args: args{
@@ -91,22 +95,25 @@ func TestGetProductsByReservationByIdAndCode(t *testing.T) {
 
 	products := []repoModel.ProductsInReservation{
 		{
-			ID:          1,
-			WarehouseId: 1,
-			ProductId:   1,
-			Quantity:    5,
+			ID:            1,
+			ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+			WarehouseId:   1,
+			ProductId:     1,
+			Quantity:      5,
 		},
 		{
-			ID:          2,
-			WarehouseId: 2,
-			ProductId:   1,
-			Quantity:    3,
+			ID:            2,
+			ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+			WarehouseId:   2,
+			ProductId:     1,
+			Quantity:      3,
 		},
 		{
-			ID:          3,
-			WarehouseId: 3,
-			ProductId:   1,
-			Quantity:    1,
+			ID:            3,
+			ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+			WarehouseId:   3,
+			ProductId:     1,
+			Quantity:      1,
 		},
 	}
 
@@ -120,22 +127,23 @@ func TestGetProductsByReservationByIdAndCode(t *testing.T) {
 		{
 			name: "Success",
 			mockBehavior: func(args args) {
-				expectedExecInReservation := `SELECT r.id, r.warehouse_id, r.product_id, r.quantity 
+				expectedExecInReservation := `SELECT r.id, r.reservation_id, r.warehouse_id, r.product_id, r.quantity 
 				FROM reservation r 
 				JOIN product p ON p.id = r.product_id
 				JOIN warehouse w ON w.id = r.warehouse_id
-				WHERE p.code = $1 AND r.reservation_id = $2
+				WHERE p.code = $1 AND r.reservation_id = $2 AND r.status = $3
 				ORDER BY r.quantity DESC`
 				mock.ExpectQuery(regexp.QuoteMeta(expectedExecInReservation)).
 					WithArgs(
 						args.productCode,
 						args.reservationId,
+						model.ReservationReserved,
 					).WillReturnRows(
 					sqlmock.NewRows(
-						[]string{"r.id", "r.warehouse_id", "r.product_id", "r.quantity"},
-					).AddRow(products[0].ID, products[0].WarehouseId, products[0].ProductId, products[0].Quantity).
-						AddRow(products[1].ID, products[1].WarehouseId, products[1].ProductId, products[1].Quantity).
-						AddRow(products[2].ID, products[2].WarehouseId, products[2].ProductId, products[2].Quantity))
+						[]string{"r.id", "r.reservation_id", "r.warehouse_id", "r.product_id", "r.quantity"},
+					).AddRow(products[0].ID, products[0].ReservationId, products[0].WarehouseId, products[0].ProductId, products[0].Quantity).
+						AddRow(products[1].ID, products[1].ReservationId, products[1].WarehouseId, products[1].ProductId, products[1].Quantity).
+						AddRow(products[2].ID, products[2].ReservationId, products[2].WarehouseId, products[2].ProductId, products[2].Quantity))
 			},
 			args: args{
 				reservationId: "1",
@@ -160,3 +168,331 @@ func TestGetProductsByReservationByIdAndCode(t *testing.T) {
 		})
 	}
 }
+
+func TestGetExpiredReservations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		limit int
+	}
+
+	type mockBehavior func(args args)
+
+	products := []repoModel.ProductsInReservation{
+		{
+			ID:            4,
+			ReservationId: "965ac486-0451-4e87-be55-2f985cdbf292",
+			WarehouseId:   1,
+			ProductId:     2,
+			Quantity:      3,
+		},
+		{
+			ID:            7,
+			ReservationId: "965ac486-0451-4e87-be55-2f985cdbf292",
+			WarehouseId:   2,
+			ProductId:     5,
+			Quantity:      1,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantProducts []repoModel.ProductsInReservation
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				expectedQuery := `SELECT id, reservation_id, warehouse_id, product_id, quantity
+				FROM reservation
+				WHERE (expires_at <= CURRENT_TIMESTAMP AND status = $1)
+				ORDER BY expires_at LIMIT 100 FOR UPDATE SKIP LOCKED`
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(model.ReservationReserved).
+					WillReturnRows(
+						sqlmock.NewRows(
+							[]string{"id", "reservation_id", "warehouse_id", "product_id", "quantity"},
+						).AddRow(products[0].ID, products[0].ReservationId, products[0].WarehouseId, products[0].ProductId, products[0].Quantity).
+							AddRow(products[1].ID, products[1].ReservationId, products[1].WarehouseId, products[1].ProductId, products[1].Quantity))
+			},
+			args:         args{limit: 100},
+			wantProducts: products,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotProducts, err := r.GetExpiredReservations(context.Background(), tt.args.limit)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantProducts, gotProducts)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetReservationStatuses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		reservationId string
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantStatuses []model.ReservationStatus
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				expectedQuery := "SELECT status FROM reservation WHERE reservation_id = $1 FOR UPDATE"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.reservationId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("reserved").AddRow("released"))
+			},
+			args:         args{reservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1"},
+			wantStatuses: []model.ReservationStatus{model.ReservationReserved, model.ReservationReleased},
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				expectedQuery := "SELECT status FROM reservation WHERE reservation_id = $1 FOR UPDATE"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.reservationId).
+					WillReturnError(errors.New("any error"))
+			},
+			args:    args{reservationId: "1"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotStatuses, err := r.GetReservationStatuses(context.Background(), tt.args.reservationId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantStatuses, gotStatuses)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetReservationById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		reservationId string
+	}
+
+	type mockBehavior func(args args)
+
+	reservedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	lines := []model.ReservationLine{
+		{
+			Code:          "12345",
+			Name:          "Lacoste T-Shirt",
+			Size:          "XS",
+			WarehouseId:   1,
+			WarehouseName: "Domodedovo",
+			Quantity:      3,
+			ReservedAt:    reservedAt,
+			Status:        model.ReservationReserved,
+		},
+		{
+			Code:          "12345",
+			Name:          "Lacoste T-Shirt",
+			Size:          "XS",
+			WarehouseId:   2,
+			WarehouseName: "Sharikovo",
+			Quantity:      2,
+			ReservedAt:    reservedAt,
+			Status:        model.ReservationReserved,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantLines    []model.ReservationLine
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				expectedQuery := `SELECT p.code, p.name, p.size, w.id, w.name, r.quantity, r.reserved_at, r.status
+				FROM reservation r
+				JOIN product p ON p.id = r.product_id
+				JOIN warehouse w ON w.id = r.warehouse_id
+				WHERE r.reservation_id = $1
+				ORDER BY p.code, w.id`
+				rows := sqlmock.NewRows([]string{"code", "name", "size", "id", "name", "quantity", "reserved_at", "status"})
+				for _, line := range lines {
+					rows.AddRow(line.Code, line.Name, line.Size, line.WarehouseId, line.WarehouseName, line.Quantity, line.ReservedAt, string(line.Status))
+				}
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.reservationId).
+					WillReturnRows(rows)
+			},
+			args:      args{reservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1"},
+			wantLines: lines,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotLines, err := r.GetReservationById(context.Background(), tt.args.reservationId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantLines, gotLines)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetProductsByReservationId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		reservationId string
+	}
+
+	type mockBehavior func(args args)
+
+	products := []repoModel.ProductsInReservation{
+		{
+			ID:            1,
+			ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+			WarehouseId:   1,
+			ProductId:     1,
+			Code:          "12345",
+			Quantity:      3,
+		},
+		{
+			ID:            2,
+			ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+			WarehouseId:   2,
+			ProductId:     1,
+			Code:          "12345",
+			Quantity:      2,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantProducts []repoModel.ProductsInReservation
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				expectedQuery := `SELECT r.id, r.reservation_id, r.warehouse_id, r.product_id, p.code, r.quantity
+				FROM reservation r
+				JOIN product p ON p.id = r.product_id
+				WHERE r.reservation_id = $1 AND r.status = $2
+				ORDER BY p.code, r.warehouse_id FOR UPDATE OF r`
+				rows := sqlmock.NewRows([]string{"id", "reservation_id", "warehouse_id", "product_id", "code", "quantity"})
+				for _, product := range products {
+					rows.AddRow(product.ID, product.ReservationId, product.WarehouseId, product.ProductId, product.Code, product.Quantity)
+				}
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.reservationId, model.ReservationReserved).
+					WillReturnRows(rows)
+			},
+			args:         args{reservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1"},
+			wantProducts: products,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotProducts, err := r.GetProductsByReservationId(context.Background(), tt.args.reservationId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantProducts, gotProducts)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetReservedQuantity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(quantity), 0) FROM reservation WHERE product_id = $1 AND status = $2 AND warehouse_id = $3")).
+		WithArgs(1, model.ReservationReserved, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(2))
+
+	gotReserved, err := r.GetReservedQuantity(context.Background(), 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, gotReserved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/warehouse-api/internal/service/model"
 )
 
 func updateReservationQuantityBuilder(id, quantity int) (string, []interface{}, error) {
@@ -21,7 +22,77 @@ func (r *repo) UpdateReservationQuantity(ctx context.Context, id, quantity int)
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	_, err = r.getExecutor(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func markReservationsExpiredBuilder(ids []int) (string, []interface{}, error) {
+	builder := sq.Update(reservation).
+		Set("expired_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Set("status", model.ReservationExpired).
+		Where(sq.Eq{"id": ids}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) MarkReservationsExpired(ctx context.Context, ids []int) error {
+	query, args, err := markReservationsExpiredBuilder(ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.getExecutor(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func updateReservationStatusBuilder(id int, status model.ReservationStatus) (string, []interface{}, error) {
+	builder := sq.Update(reservation).
+		Where(sq.Eq{"id": id}).
+		Set("status", status).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) UpdateReservationStatus(ctx context.Context, id int, status model.ReservationStatus) error {
+	query, args, err := updateReservationStatusBuilder(id, status)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.getExecutor(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func updateReservationsStatusBuilder(reservationId string, from, to model.ReservationStatus) (string, []interface{}, error) {
+	builder := sq.Update(reservation).
+		Where(sq.Eq{"reservation_id": reservationId, "status": from}).
+		Set("status", to).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) UpdateReservationsStatus(ctx context.Context, reservationId string, from, to model.ReservationStatus) error {
+	query, args, err := updateReservationsStatusBuilder(reservationId, from, to)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.getExecutor(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
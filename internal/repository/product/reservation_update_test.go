@@ -9,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/service/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -79,3 +80,139 @@ func TestUpdateReservationQuantity(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkReservationsExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		ids []int
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			args: args{
+				ids: []int{4, 7},
+			},
+			mockBehavior: func(args args) {
+				expectedQuery := "UPDATE reservation SET expired_at = CURRENT_TIMESTAMP, status = $1 WHERE id IN ($2,$3)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(model.ReservationExpired, args.ids[0], args.ids[1]).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "Failed",
+			args: args{
+				ids: []int{4},
+			},
+			mockBehavior: func(args args) {
+				expectedQuery := "UPDATE reservation SET expired_at = CURRENT_TIMESTAMP, status = $1 WHERE id IN ($2)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(model.ReservationExpired, args.ids[0]).
+					WillReturnError(errors.New("any error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			err := r.MarkReservationsExpired(context.Background(), tt.args.ids)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateReservationsStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		reservationId string
+		from          model.ReservationStatus
+		to            model.ReservationStatus
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			args: args{
+				reservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+				from:          model.ReservationReserved,
+				to:            model.ReservationCommitted,
+			},
+			mockBehavior: func(args args) {
+				expectedQuery := "UPDATE reservation SET status = $1 WHERE reservation_id = $2 AND status = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.to, args.reservationId, args.from).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "Failed",
+			args: args{
+				reservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+				from:          model.ReservationReserved,
+				to:            model.ReservationCommitted,
+			},
+			mockBehavior: func(args args) {
+				expectedQuery := "UPDATE reservation SET status = $1 WHERE reservation_id = $2 AND status = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.to, args.reservationId, args.from).
+					WillReturnError(errors.New("any error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			err := r.UpdateReservationsStatus(context.Background(), tt.args.reservationId, tt.args.from, tt.args.to)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
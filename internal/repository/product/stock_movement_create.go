@@ -0,0 +1,33 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+)
+
+func createStockMovementBuilder(movement repoModel.StockMovement) (string, []interface{}, error) {
+	builder := sq.Insert(stockMovement).
+		Columns("warehouse_id", "product_id", "delta", "reason", "reference_id").
+		Values(movement.WarehouseId, movement.ProductId, movement.Delta, movement.Reason, movement.ReferenceId).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// CreateStockMovement appends a record to the stock ledger. It must run in the same
+// transaction as the quantity change it describes.
+func (r *repo) CreateStockMovement(ctx context.Context, movement repoModel.StockMovement) error {
+	query, args, err := createStockMovementBuilder(movement)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.getExecutor(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
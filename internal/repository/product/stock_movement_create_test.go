@@ -0,0 +1,42 @@
+package product
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStockMovement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	movement := repoModel.StockMovement{
+		WarehouseId: 2,
+		ProductId:   1,
+		Delta:       -3,
+		Reason:      model.MovementReserve,
+		ReferenceId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO stock_movement (warehouse_id,product_id,delta,reason,reference_id) VALUES ($1,$2,$3,$4,$5)")).
+		WithArgs(movement.WarehouseId, movement.ProductId, movement.Delta, movement.Reason, movement.ReferenceId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = r.CreateStockMovement(context.Background(), movement)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
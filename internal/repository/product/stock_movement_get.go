@@ -0,0 +1,70 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+func getStockMovementsBuilder(filter repoModel.StockMovementFilter) (string, []interface{}, error) {
+	where := sq.And{}
+	if filter.Code != "" {
+		where = append(where, sq.Eq{"p.code": filter.Code})
+	}
+	if filter.WarehouseId != 0 {
+		where = append(where, sq.Eq{"sm.warehouse_id": filter.WarehouseId})
+	}
+	if filter.From != nil {
+		where = append(where, sq.GtOrEq{"sm.created_at": *filter.From})
+	}
+	if filter.To != nil {
+		where = append(where, sq.Lt{"sm.created_at": *filter.To})
+	}
+
+	builder := sq.Select("sm.id", "sm.warehouse_id", "p.code", "sm.delta", "sm.reason", "sm.reference_id", "sm.created_at").
+		From(stockMovement+" sm").
+		Join(product+" p ON p.id = sm.product_id").
+		Where(where).
+		OrderBy("sm.created_at", "sm.id").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetStockMovements(ctx context.Context, filter repoModel.StockMovementFilter) ([]model.StockMovement, error) {
+	query, args, err := getStockMovementsBuilder(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var movements []model.StockMovement
+	for rows.Next() {
+		var movement model.StockMovement
+
+		err = rows.Scan(&movement.ID, &movement.WarehouseId, &movement.Code, &movement.Delta,
+			&movement.Reason, &movement.ReferenceId, &movement.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, movement)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return movements, nil
+}
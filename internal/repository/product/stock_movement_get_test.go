@@ -0,0 +1,93 @@
+package product
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStockMovements(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		filter repoModel.StockMovementFilter
+	}
+
+	type mockBehavior func(args args)
+
+	from := time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	columns := []string{"id", "warehouse_id", "code", "delta", "reason", "reference_id", "created_at"}
+	movements := []model.StockMovement{
+		{ID: 1, WarehouseId: 2, Code: "12345", Delta: -3, Reason: model.MovementReserve, ReferenceId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1", CreatedAt: from.Add(time.Hour)},
+		{ID: 5, WarehouseId: 2, Code: "12345", Delta: 3, Reason: model.MovementExpire, ReferenceId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1", CreatedAt: from.Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		args          args
+		wantMovements []model.StockMovement
+	}{
+		{
+			name: "All filters",
+			mockBehavior: func(args args) {
+				expectedQuery := "SELECT sm.id, sm.warehouse_id, p.code, sm.delta, sm.reason, sm.reference_id, sm.created_at " +
+					"FROM stock_movement sm JOIN product p ON p.id = sm.product_id " +
+					"WHERE (p.code = $1 AND sm.warehouse_id = $2 AND sm.created_at >= $3 AND sm.created_at < $4) " +
+					"ORDER BY sm.created_at, sm.id LIMIT 100 OFFSET 0"
+				rows := sqlmock.NewRows(columns)
+				for _, m := range movements {
+					rows.AddRow(m.ID, m.WarehouseId, m.Code, m.Delta, string(m.Reason), m.ReferenceId, m.CreatedAt)
+				}
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs("12345", 2, from, to).
+					WillReturnRows(rows)
+			},
+			args: args{
+				filter: repoModel.StockMovementFilter{Code: "12345", WarehouseId: 2, From: &from, To: &to, Limit: 100},
+			},
+			wantMovements: movements,
+		},
+		{
+			name: "Without filters",
+			mockBehavior: func(args args) {
+				expectedQuery := "SELECT sm.id, sm.warehouse_id, p.code, sm.delta, sm.reason, sm.reference_id, sm.created_at " +
+					"FROM stock_movement sm JOIN product p ON p.id = sm.product_id " +
+					"WHERE (1=1) ORDER BY sm.created_at, sm.id LIMIT 10 OFFSET 20"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			args: args{
+				filter: repoModel.StockMovementFilter{Limit: 10, Offset: 20},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotMovements, err := r.GetStockMovements(context.Background(), tt.args.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMovements, gotMovements)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+func createTransferBuilder(productId, fromWarehouseId, toWarehouseId, quantity int, status model.TransferStatus) (string, []interface{}, error) {
+	columns := []string{"product_id", "from_warehouse_id", "to_warehouse_id", "quantity", "status"}
+	values := []interface{}{productId, fromWarehouseId, toWarehouseId, quantity, status}
+
+	if status == model.TransferReceived {
+		columns = append(columns, "received_at")
+		values = append(values, sq.Expr("CURRENT_TIMESTAMP"))
+	}
+
+	builder := sq.Insert(transfer).
+		Columns(columns...).
+		Values(values...).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) CreateTransfer(ctx context.Context, productId, fromWarehouseId, toWarehouseId, quantity int, status model.TransferStatus) (int, error) {
+	query, args, err := createTransferBuilder(productId, fromWarehouseId, toWarehouseId, quantity, status)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
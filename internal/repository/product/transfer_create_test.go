@@ -0,0 +1,90 @@
+package product
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		productId       int
+		fromWarehouseId int
+		toWarehouseId   int
+		quantity        int
+		status          model.TransferStatus
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantId       int
+	}{
+		{
+			name: "In transit",
+			mockBehavior: func(args args) {
+				expectedQuery := "INSERT INTO transfer (product_id,from_warehouse_id,to_warehouse_id,quantity,status) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.productId, args.fromWarehouseId, args.toWarehouseId, args.quantity, args.status).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			args: args{
+				productId:       9,
+				fromWarehouseId: 3,
+				toWarehouseId:   1,
+				quantity:        2,
+				status:          model.TransferShipped,
+			},
+			wantId: 1,
+		},
+		{
+			name: "Received at once",
+			mockBehavior: func(args args) {
+				expectedQuery := "INSERT INTO transfer (product_id,from_warehouse_id,to_warehouse_id,quantity,status,received_at) " +
+					"VALUES ($1,$2,$3,$4,$5,CURRENT_TIMESTAMP) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.productId, args.fromWarehouseId, args.toWarehouseId, args.quantity, args.status).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			},
+			args: args{
+				productId:       9,
+				fromWarehouseId: 3,
+				toWarehouseId:   2,
+				quantity:        1,
+				status:          model.TransferReceived,
+			},
+			wantId: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotId, err := r.CreateTransfer(context.Background(), tt.args.productId, tt.args.fromWarehouseId,
+				tt.args.toWarehouseId, tt.args.quantity, tt.args.status)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantId, gotId)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+)
+
+func getTransferByIdBuilder(id int) (string, []interface{}, error) {
+	builder := sq.Select("t.id", "t.product_id", "p.code", "t.from_warehouse_id", "t.to_warehouse_id",
+		"t.quantity", "t.status", "t.shipped_at", "t.received_at").
+		From(transfer + " t").
+		Join(product + " p ON p.id = t.product_id").
+		Where(sq.Eq{"t.id": id}).
+		Suffix("FOR UPDATE OF t").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetTransferById(ctx context.Context, id int) (repoModel.Transfer, error) {
+	query, args, err := getTransferByIdBuilder(id)
+	if err != nil {
+		return repoModel.Transfer{}, err
+	}
+
+	var t repoModel.Transfer
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&t.ID, &t.ProductId, &t.Code,
+		&t.FromWarehouseId, &t.ToWarehouseId, &t.Quantity, &t.Status, &t.ShippedAt, &t.ReceivedAt)
+	if err != nil {
+		return repoModel.Transfer{}, err
+	}
+
+	return t, nil
+}
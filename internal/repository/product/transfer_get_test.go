@@ -0,0 +1,113 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransferById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		id int
+	}
+
+	type mockBehavior func(args args)
+
+	shippedAt := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	receivedAt := shippedAt.Add(time.Hour)
+	columns := []string{"id", "product_id", "code", "from_warehouse_id", "to_warehouse_id", "quantity", "status", "shipped_at", "received_at"}
+	expectedQuery := "SELECT t.id, t.product_id, p.code, t.from_warehouse_id, t.to_warehouse_id, t.quantity, t.status, t.shipped_at, t.received_at " +
+		"FROM transfer t JOIN product p ON p.id = t.product_id WHERE t.id = $1 FOR UPDATE OF t"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantTransfer repoModel.Transfer
+		wantErr      error
+	}{
+		{
+			name: "In transit",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 9, "10101011", 3, 1, 2, "shipped", shippedAt, nil))
+			},
+			args: args{id: 1},
+			wantTransfer: repoModel.Transfer{
+				ID:              1,
+				ProductId:       9,
+				Code:            "10101011",
+				FromWarehouseId: 3,
+				ToWarehouseId:   1,
+				Quantity:        2,
+				Status:          model.TransferShipped,
+				ShippedAt:       shippedAt,
+			},
+		},
+		{
+			name: "Received",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 9, "10101011", 3, 2, 1, "received", shippedAt, receivedAt))
+			},
+			args: args{id: 2},
+			wantTransfer: repoModel.Transfer{
+				ID:              2,
+				ProductId:       9,
+				Code:            "10101011",
+				FromWarehouseId: 3,
+				ToWarehouseId:   2,
+				Quantity:        1,
+				Status:          model.TransferReceived,
+				ShippedAt:       shippedAt,
+				ReceivedAt:      &receivedAt,
+			},
+		},
+		{
+			name: "Not found",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			args:    args{id: 100},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotTransfer, err := r.GetTransferById(context.Background(), tt.args.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTransfer, gotTransfer)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+func updateTransferStatusBuilder(id int, status model.TransferStatus) (string, []interface{}, error) {
+	builder := sq.Update(transfer).
+		Set("status", status).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar)
+
+	if status == model.TransferReceived {
+		builder = builder.Set("received_at", sq.Expr("CURRENT_TIMESTAMP"))
+	}
+
+	return builder.ToSql()
+}
+
+func (r *repo) UpdateTransferStatus(ctx context.Context, id int, status model.TransferStatus) error {
+	query, args, err := updateTransferStatusBuilder(id, status)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.getExecutor(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package product
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTransferStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE transfer SET status = $1, received_at = CURRENT_TIMESTAMP WHERE id = $2")).
+		WithArgs(model.TransferReceived, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = r.UpdateTransferStatus(context.Background(), 1, model.TransferReceived)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
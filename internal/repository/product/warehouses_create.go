@@ -0,0 +1,29 @@
+package product
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+func createWarehouseBuilder(wh model.Warehouse) (string, []interface{}, error) {
+	latitude, longitude := fromGeoPoint(wh.Location)
+
+	builder := sq.Insert(warehouse).
+		Columns("name", "availability", "priority", "region", "latitude", "longitude").
+		Values(wh.Name, wh.Availability, wh.Priority, wh.Region, latitude, longitude).
+		Suffix("RETURNING " + warehouseColumns).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) CreateWarehouse(ctx context.Context, wh model.Warehouse) (model.Warehouse, error) {
+	query, args, err := createWarehouseBuilder(wh)
+	if err != nil {
+		return model.Warehouse{}, err
+	}
+
+	return scanWarehouse(r.getExecutor(ctx).QueryRowxContext(ctx, query, args...))
+}
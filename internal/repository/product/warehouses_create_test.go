@@ -0,0 +1,94 @@
+package product
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWarehouse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		wh model.Warehouse
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "INSERT INTO warehouse (name,availability,priority,region,latitude,longitude) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id, name, availability, priority, region, latitude, longitude"
+
+	tests := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		args          args
+		wantWarehouse model.Warehouse
+		wantErr       bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.wh.Name, args.wh.Availability, args.wh.Priority, args.wh.Region, 55.43, 37.55).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "availability", "priority", "region", "latitude", "longitude"}).AddRow(4, args.wh.Name, args.wh.Availability, args.wh.Priority, args.wh.Region, 55.43, 37.55))
+			},
+			args: args{
+				wh: model.Warehouse{Name: "Podolsk", Availability: true, Priority: 2, Region: "moscow", Location: &model.GeoPoint{Latitude: 55.43, Longitude: 37.55}},
+			},
+			wantWarehouse: model.Warehouse{ID: 4, Name: "Podolsk", Availability: true, Priority: 2, Region: "moscow", Location: &model.GeoPoint{Latitude: 55.43, Longitude: 37.55}},
+		},
+		{
+			name: "Success without location",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.wh.Name, args.wh.Availability, 0, "", nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "availability", "priority", "region", "latitude", "longitude"}).AddRow(5, args.wh.Name, args.wh.Availability, 0, "", nil, nil))
+			},
+			args: args{
+				wh: model.Warehouse{Name: "Khimki"},
+			},
+			wantWarehouse: model.Warehouse{ID: 5, Name: "Khimki"},
+		},
+		{
+			name: "Duplicate name",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.wh.Name, false, 0, "", nil, nil).
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+			},
+			args: args{
+				wh: model.Warehouse{Name: "Domodedovo"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotWarehouse, err := r.CreateWarehouse(context.Background(), tt.args.wh)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantWarehouse, gotWarehouse)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -2,7 +2,7 @@ package product
 
 import (
 	"context"
-	"time"
+	"database/sql"
 
 	sq "github.com/Masterminds/squirrel"
 	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
@@ -25,7 +25,7 @@ func (r *repo) GetProductsByWarehouseId(ctx context.Context, id int) ([]model.Pr
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -81,35 +81,40 @@ func (r *repo) GetTotalQuantityOfProducts(ctx context.Context, code string) (int
 	`
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query, code).Scan(&count)
+	err := r.getExecutor(ctx).QueryRowxContext(ctx, query, code).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
 
-	time.Sleep(5 * time.Minute)
-
 	return count, nil
 }
 
-func getProductsByWarehousesByCodeBuilder(code string) (string, []interface{}, error) {
-	builder := sq.Select("wp.warehouse_id, wp.product_id, wp.quantity").
+func getProductsByWarehousesByCodeBuilder(code string, warehouseIds []int) (string, []interface{}, error) {
+	where := sq.Eq{"p.code": code, "w.availability": true}
+	if len(warehouseIds) > 0 {
+		where["wp.warehouse_id"] = warehouseIds
+	}
+
+	builder := sq.Select("wp.warehouse_id, wp.product_id, wp.quantity, w.priority, w.region, w.latitude, w.longitude").
 		From(warehouseProduct + " wp").
 		Join(product + " p ON p.id = wp.product_id").
 		Join(warehouse + " w ON w.id = wp.warehouse_id").
-		Where(sq.Eq{"p.code": code, "w.availability": true}).
+		Where(where).
 		OrderBy("wp.quantity DESC").
 		PlaceholderFormat(sq.Dollar)
 
 	return builder.ToSql()
 }
 
-func (r *repo) GetProductsByWarehousesByCode(ctx context.Context, code string) ([]repoModel.ProductsOnActiveWarehouse, error) {
-	query, args, err := getProductsByWarehousesByCodeBuilder(code)
+// GetProductsByWarehousesByCode returns stock of the product on active warehouses,
+// limited to warehouseIds when the list is not empty
+func (r *repo) GetProductsByWarehousesByCode(ctx context.Context, code string, warehouseIds []int) ([]repoModel.ProductsOnActiveWarehouse, error) {
+	query, args, err := getProductsByWarehousesByCodeBuilder(code, warehouseIds)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -119,12 +124,16 @@ func (r *repo) GetProductsByWarehousesByCode(ctx context.Context, code string) (
 
 	var ProductsByWHs []repoModel.ProductsOnActiveWarehouse
 	for rows.Next() {
-		var ProductsByWH repoModel.ProductsOnActiveWarehouse
+		var (
+			ProductsByWH        repoModel.ProductsOnActiveWarehouse
+			latitude, longitude sql.NullFloat64
+		)
 
-		err = rows.Scan(&ProductsByWH.WarehouseId, &ProductsByWH.ProductId, &ProductsByWH.Quantity)
+		err = rows.Scan(&ProductsByWH.WarehouseId, &ProductsByWH.ProductId, &ProductsByWH.Quantity, &ProductsByWH.Priority, &ProductsByWH.Region, &latitude, &longitude)
 		if err != nil {
 			return nil, err
 		}
+		ProductsByWH.Location = toGeoPoint(latitude, longitude)
 
 		ProductsByWHs = append(ProductsByWHs, ProductsByWH)
 	}
@@ -152,10 +161,116 @@ func (r *repo) GetWarehouseAvailabilityById(ctx context.Context, id int) (bool,
 	}
 
 	var isAvailable bool
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&isAvailable)
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&isAvailable)
 	if err != nil {
 		return false, err
 	}
 
 	return isAvailable, nil
 }
+
+// warehouseColumns are the columns scanned by scanWarehouse
+const warehouseColumns = "id, name, availability, priority, region, latitude, longitude"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWarehouse(row rowScanner) (model.Warehouse, error) {
+	var (
+		wh                  model.Warehouse
+		latitude, longitude sql.NullFloat64
+	)
+
+	err := row.Scan(&wh.ID, &wh.Name, &wh.Availability, &wh.Priority, &wh.Region, &latitude, &longitude)
+	if err != nil {
+		return model.Warehouse{}, err
+	}
+	wh.Location = toGeoPoint(latitude, longitude)
+
+	return wh, nil
+}
+
+func toGeoPoint(latitude, longitude sql.NullFloat64) *model.GeoPoint {
+	if !latitude.Valid || !longitude.Valid {
+		return nil
+	}
+
+	return &model.GeoPoint{Latitude: latitude.Float64, Longitude: longitude.Float64}
+}
+
+// fromGeoPoint returns latitude and longitude column values, NULL for a missing location
+func fromGeoPoint(location *model.GeoPoint) (interface{}, interface{}) {
+	if location == nil {
+		return nil, nil
+	}
+
+	return location.Latitude, location.Longitude
+}
+
+func getWarehousesBuilder() (string, []interface{}, error) {
+	builder := sq.Select(warehouseColumns).
+		From(warehouse).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) GetWarehouses(ctx context.Context) ([]model.Warehouse, error) {
+	query, args, err := getWarehousesBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var warehouses []model.Warehouse
+	for rows.Next() {
+		wh, err := scanWarehouse(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		warehouses = append(warehouses, wh)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return warehouses, nil
+}
+
+func getWarehouseQuantityBuilder(warehouseId, productId int) (string, []interface{}, error) {
+	builder := sq.Select("quantity").
+		From(warehouseProduct).
+		Where(sq.Eq{"warehouse_id": warehouseId, "product_id": productId}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetWarehouseQuantity locks and returns the available quantity of the product in the warehouse.
+// It returns sql.ErrNoRows if the warehouse has never held the product.
+func (r *repo) GetWarehouseQuantity(ctx context.Context, warehouseId, productId int) (int, error) {
+	query, args, err := getWarehouseQuantityBuilder(warehouseId, productId)
+	if err != nil {
+		return 0, err
+	}
+
+	var quantity int
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	return quantity, nil
+}
@@ -2,12 +2,14 @@ package product
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
 	"github.com/pintoter/warehouse-api/internal/service/model"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -123,3 +125,215 @@ func TestGetProductsByWarehouseId(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	}
 }
+
+func TestGetWarehouses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type mockBehavior func()
+
+	warehouses := []model.Warehouse{
+		{ID: 1, Name: "Domodedovo", Availability: true},
+		{ID: 2, Name: "Sharikovo", Availability: true},
+		{ID: 3, Name: "Molchanovo", Availability: false},
+	}
+
+	tests := []struct {
+		name           string
+		mockBehavior   mockBehavior
+		wantWarehouses []model.Warehouse
+		wantErr        bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				rows := sqlmock.NewRows([]string{"id", "name", "availability", "priority", "region", "latitude", "longitude"})
+				for _, wh := range warehouses {
+					rows.AddRow(wh.ID, wh.Name, wh.Availability, wh.Priority, wh.Region, nil, nil)
+				}
+
+				expectedQuery := "SELECT id, name, availability, priority, region, latitude, longitude FROM warehouse ORDER BY id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WillReturnRows(rows)
+			},
+			wantWarehouses: warehouses,
+		},
+		{
+			name: "Failed",
+			mockBehavior: func() {
+				expectedQuery := "SELECT id, name, availability, priority, region, latitude, longitude FROM warehouse ORDER BY id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WillReturnError(errors.New("some error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			gotWarehouses, err := r.GetWarehouses(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantWarehouses, gotWarehouses)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetProductsByWarehousesByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		code         string
+		warehouseIds []int
+	}
+
+	type mockBehavior func(args args)
+
+	columns := []string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}
+	expectedQuery := "SELECT wp.warehouse_id, wp.product_id, wp.quantity, w.priority, w.region, w.latitude, w.longitude FROM warehouse_product wp JOIN product p ON p.id = wp.product_id JOIN warehouse w ON w.id = wp.warehouse_id"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantProducts []repoModel.ProductsOnActiveWarehouse
+		wantErr      bool
+	}{
+		{
+			name: "All warehouses",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery+" WHERE p.code = $1 AND w.availability = $2 ORDER BY wp.quantity DESC")).
+					WithArgs(args.code, true).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 4, 7, 2, "moscow", 55.41, 37.9).
+						AddRow(2, 4, 3, 0, "", nil, nil))
+			},
+			args: args{code: "12345"},
+			wantProducts: []repoModel.ProductsOnActiveWarehouse{
+				{WarehouseId: 1, ProductId: 4, Quantity: 7, Priority: 2, Region: "moscow", Location: &model.GeoPoint{Latitude: 55.41, Longitude: 37.9}},
+				{WarehouseId: 2, ProductId: 4, Quantity: 3},
+			},
+		},
+		{
+			name: "Allowed warehouses",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery+" WHERE p.code = $1 AND w.availability = $2 AND wp.warehouse_id IN ($3,$4) ORDER BY wp.quantity DESC")).
+					WithArgs(args.code, true, 2, 3).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 4, 3, 0, "", nil, nil))
+			},
+			args: args{code: "12345", warehouseIds: []int{2, 3}},
+			wantProducts: []repoModel.ProductsOnActiveWarehouse{
+				{WarehouseId: 2, ProductId: 4, Quantity: 3},
+			},
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.code, true).
+					WillReturnError(errors.New("some error"))
+			},
+			args:    args{code: "12345"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotProducts, err := r.GetProductsByWarehousesByCode(context.Background(), tt.args.code, tt.args.warehouseIds)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantProducts, gotProducts)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetWarehouseQuantity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		warehouseId int
+		productId   int
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "SELECT quantity FROM warehouse_product WHERE product_id = $1 AND warehouse_id = $2 FOR UPDATE"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantQuantity int
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.productId, args.warehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(5))
+			},
+			args:         args{warehouseId: 1, productId: 2},
+			wantQuantity: 5,
+		},
+		{
+			name: "Product is new to warehouse",
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.productId, args.warehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}))
+			},
+			args:    args{warehouseId: 1, productId: 15},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			gotQuantity, err := r.GetWarehouseQuantity(context.Background(), tt.args.warehouseId, tt.args.productId)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantQuantity, gotQuantity)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/warehouse-api/internal/service/model"
 )
 
 func updateBuilder(warehouseId, productId, quantity int) (string, []interface{}, error) {
@@ -21,7 +22,7 @@ func (r *repo) UpdateWarehouseQuantity(ctx context.Context, warehouseId, product
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	_, err = r.getExecutor(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -31,10 +32,90 @@ func (r *repo) UpdateWarehouseQuantity(ctx context.Context, warehouseId, product
 
 func (r *repo) UpdateWarehouseQuantityWithAdd(ctx context.Context, warehouseId, productId, quantity int) error {
 	query := "UPDATE warehouse_product SET quantity = quantity + $1 WHERE product_id = $2 AND warehouse_id = $3"
-	_, err := r.db.ExecContext(ctx, query, quantity, productId, warehouseId)
+	_, err := r.getExecutor(ctx).ExecContext(ctx, query, quantity, productId, warehouseId)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func updateWarehouseBuilder(id int, values map[string]interface{}) (string, []interface{}, error) {
+	builder := sq.Update(warehouse).
+		Where(sq.Eq{"id": id}).
+		SetMap(values).
+		Suffix("RETURNING " + warehouseColumns).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *repo) updateWarehouse(ctx context.Context, id int, values map[string]interface{}) (model.Warehouse, error) {
+	query, args, err := updateWarehouseBuilder(id, values)
+	if err != nil {
+		return model.Warehouse{}, err
+	}
+
+	return scanWarehouse(r.getExecutor(ctx).QueryRowxContext(ctx, query, args...))
+}
+
+func (r *repo) UpdateWarehouseName(ctx context.Context, id int, name string) (model.Warehouse, error) {
+	return r.updateWarehouse(ctx, id, map[string]interface{}{"name": name})
+}
+
+func (r *repo) UpdateWarehouseAvailability(ctx context.Context, id int, availability bool) (model.Warehouse, error) {
+	return r.updateWarehouse(ctx, id, map[string]interface{}{"availability": availability})
+}
+
+// UpdateWarehouseRouting sets the priority, region and location used to allocate stock from the warehouse
+func (r *repo) UpdateWarehouseRouting(ctx context.Context, id int, priority int, region string, location *model.GeoPoint) (model.Warehouse, error) {
+	latitude, longitude := fromGeoPoint(location)
+
+	return r.updateWarehouse(ctx, id, map[string]interface{}{
+		"priority":  priority,
+		"region":    region,
+		"latitude":  latitude,
+		"longitude": longitude,
+	})
+}
+
+func addWarehouseQuantityBuilder(warehouseId, productId, quantity int) (string, []interface{}, error) {
+	builder := sq.Insert(warehouseProduct).
+		Columns("warehouse_id", "product_id", "quantity").
+		Values(warehouseId, productId, quantity).
+		Suffix("ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = warehouse_product.quantity + EXCLUDED.quantity RETURNING quantity").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// AddWarehouseQuantity increases stock of the product in the warehouse, creating the row
+// if the product is new to the warehouse. It returns the resulting quantity.
+func (r *repo) AddWarehouseQuantity(ctx context.Context, warehouseId, productId, quantity int) (int, error) {
+	query, args, err := addWarehouseQuantityBuilder(warehouseId, productId, quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	err = r.getExecutor(ctx).QueryRowxContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// SubtractWarehouseQuantity decreases stock of the product in the warehouse.
+// It returns sql.ErrNoRows if the warehouse holds less than quantity.
+func (r *repo) SubtractWarehouseQuantity(ctx context.Context, warehouseId, productId, quantity int) (int, error) {
+	query := "UPDATE warehouse_product SET quantity = quantity - $1 WHERE warehouse_id = $2 AND product_id = $3 AND quantity >= $1 RETURNING quantity"
+
+	var total int
+	err := r.getExecutor(ctx).QueryRowxContext(ctx, query, quantity, warehouseId, productId).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
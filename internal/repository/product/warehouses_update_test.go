@@ -2,13 +2,16 @@ package product
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"log"
 	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
 	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/service/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -153,3 +156,237 @@ func UpdateWarehouseQuantityWithAdd(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateWarehouseName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		id   int
+		name string
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "UPDATE warehouse SET name = $1 WHERE id = $2 RETURNING id, name, availability, priority, region, latitude, longitude"
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehavior  mockBehavior
+		wantWarehouse model.Warehouse
+		wantErr       bool
+	}{
+		{
+			name: "Success",
+			args: args{
+				id:   3,
+				name: "Molchanovo-2",
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.name, args.id).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "availability", "priority", "region", "latitude", "longitude"}).AddRow(args.id, args.name, false, 0, "", nil, nil))
+			},
+			wantWarehouse: model.Warehouse{ID: 3, Name: "Molchanovo-2"},
+		},
+		{
+			name: "Not found",
+			args: args{
+				id:   100,
+				name: "Nowhere",
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.name, args.id).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "availability", "priority", "region", "latitude", "longitude"}))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			gotWarehouse, err := r.UpdateWarehouseName(context.Background(), tt.args.id, tt.args.name)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantWarehouse, gotWarehouse)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAddWarehouseQuantity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		warehouseId int
+		productId   int
+		quantity    int
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "INSERT INTO warehouse_product (warehouse_id,product_id,quantity) VALUES ($1,$2,$3) " +
+		"ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = warehouse_product.quantity + EXCLUDED.quantity RETURNING quantity"
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantTotal    int
+		wantErr      bool
+	}{
+		{
+			name: "Existing product",
+			args: args{
+				warehouseId: 1,
+				productId:   1,
+				quantity:    10,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.warehouseId, args.productId, args.quantity).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(13))
+			},
+			wantTotal: 13,
+		},
+		{
+			name: "New product for warehouse",
+			args: args{
+				warehouseId: 1,
+				productId:   15,
+				quantity:    4,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.warehouseId, args.productId, args.quantity).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(4))
+			},
+			wantTotal: 4,
+		},
+		{
+			name: "Overflow",
+			args: args{
+				warehouseId: 1,
+				productId:   1,
+				quantity:    40000,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.warehouseId, args.productId, args.quantity).
+					WillReturnError(&pgconn.PgError{Code: "22003"})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			gotTotal, err := r.AddWarehouseQuantity(context.Background(), tt.args.warehouseId, tt.args.productId, tt.args.quantity)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTotal, gotTotal)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSubtractWarehouseQuantity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	r := NewRepository(sqlxDB)
+
+	type args struct {
+		warehouseId int
+		productId   int
+		quantity    int
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "UPDATE warehouse_product SET quantity = quantity - $1 WHERE warehouse_id = $2 AND product_id = $3 AND quantity >= $1 RETURNING quantity"
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantTotal    int
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			args: args{
+				warehouseId: 3,
+				productId:   10,
+				quantity:    2,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.quantity, args.warehouseId, args.productId).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(3))
+			},
+			wantTotal: 3,
+		},
+		{
+			name: "Not enough products",
+			args: args{
+				warehouseId: 3,
+				productId:   10,
+				quantity:    20,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.quantity, args.warehouseId, args.productId).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			gotTotal, err := r.SubtractWarehouseQuantity(context.Background(), tt.args.warehouseId, tt.args.productId, tt.args.quantity)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTotal, gotTotal)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
 	"github.com/pintoter/warehouse-api/internal/service/model"
@@ -9,21 +10,65 @@ import (
 
 type WarehousesRepository interface {
 	GetProductsByWarehouseId(ctx context.Context, id int) ([]model.Product, error)
-	GetProductsByWarehousesByCode(ctx context.Context, code string) ([]repoModel.ProductsOnActiveWarehouse, error)
+	GetProductsByWarehousesByCode(ctx context.Context, code string, warehouseIds []int) ([]repoModel.ProductsOnActiveWarehouse, error)
 	GetTotalQuantityOfProducts(ctx context.Context, code string) (int, error)
 	GetWarehouseAvailabilityById(ctx context.Context, warehouseId int) (bool, error)
 	UpdateWarehouseQuantity(ctx context.Context, warehouseId, productId, quantity int) error
 	UpdateWarehouseQuantityWithAdd(ctx context.Context, warehouseId, productId, quantity int) error
+	CreateWarehouse(ctx context.Context, wh model.Warehouse) (model.Warehouse, error)
+	GetWarehouses(ctx context.Context) ([]model.Warehouse, error)
+	UpdateWarehouseName(ctx context.Context, id int, name string) (model.Warehouse, error)
+	UpdateWarehouseAvailability(ctx context.Context, id int, availability bool) (model.Warehouse, error)
+	UpdateWarehouseRouting(ctx context.Context, id int, priority int, region string, location *model.GeoPoint) (model.Warehouse, error)
+	AddWarehouseQuantity(ctx context.Context, warehouseId, productId, quantity int) (int, error)
+	SubtractWarehouseQuantity(ctx context.Context, warehouseId, productId, quantity int) (int, error)
+	GetWarehouseQuantity(ctx context.Context, warehouseId, productId int) (int, error)
 }
 
 type ReservationRepository interface {
-	CreateReservation(ctx context.Context, warehouseId, productId, quantity int, reservationId string) (int, error)
+	CreateReservation(ctx context.Context, warehouseId, productId, quantity int, reservationId string, ttlSeconds int) (int, error)
 	GetTotalQuantityOfReservation(ctx context.Context, reservationId string, productCode string) (int, error)
 	GetProductsByReservationByIdAndCode(ctx context.Context, reservationId, code string) ([]repoModel.ProductsInReservation, error)
+	GetProductsByReservationId(ctx context.Context, reservationId string) ([]repoModel.ProductsInReservation, error)
 	UpdateReservationQuantity(ctx context.Context, id, quantity int) error
+	GetReservationById(ctx context.Context, reservationId string) ([]model.ReservationLine, error)
+	GetReservationStatuses(ctx context.Context, reservationId string) ([]model.ReservationStatus, error)
+	UpdateReservationStatus(ctx context.Context, id int, status model.ReservationStatus) error
+	UpdateReservationsStatus(ctx context.Context, reservationId string, from, to model.ReservationStatus) error
+	GetExpiredReservations(ctx context.Context, limit int) ([]repoModel.ProductsInReservation, error)
+	MarkReservationsExpired(ctx context.Context, ids []int) error
+	GetReservedQuantity(ctx context.Context, warehouseId, productId int) (int, error)
+}
+
+type ProductsRepository interface {
+	CreateProduct(ctx context.Context, product model.CatalogProduct) (model.CatalogProduct, error)
+	UpdateProduct(ctx context.Context, product model.CatalogProduct) (model.CatalogProduct, error)
+	GetProductByCode(ctx context.Context, code string) (model.CatalogProduct, error)
+	GetProducts(ctx context.Context, filter repoModel.ProductFilter) ([]model.CatalogProduct, error)
+	GetProductsCount(ctx context.Context, filter repoModel.ProductFilter) (int, error)
+}
+
+type InventoryRepository interface {
+	CreateReceipt(ctx context.Context, supplierRef string, warehouseId int) (int, time.Time, error)
+	CreateReceiptLine(ctx context.Context, receiptId, productId, quantity int) error
+	CreateTransfer(ctx context.Context, productId, fromWarehouseId, toWarehouseId, quantity int, status model.TransferStatus) (int, error)
+	GetTransferById(ctx context.Context, id int) (repoModel.Transfer, error)
+	UpdateTransferStatus(ctx context.Context, id int, status model.TransferStatus) error
+	CreateStockMovement(ctx context.Context, movement repoModel.StockMovement) error
+	GetStockMovements(ctx context.Context, filter repoModel.StockMovementFilter) ([]model.StockMovement, error)
+	CreateStockAdjustment(ctx context.Context, adjustment repoModel.StockAdjustment) (int, error)
+}
+
+type IdempotencyRepository interface {
+	CreateIdempotencyKey(ctx context.Context, method, key, requestHash string) (bool, error)
+	GetIdempotencyKey(ctx context.Context, method, key string) (repoModel.IdempotencyKey, error)
+	UpdateIdempotencyResponse(ctx context.Context, method, key string, response []byte) error
 }
 
 type Repository interface {
 	WarehousesRepository
 	ReservationRepository
+	ProductsRepository
+	InventoryRepository
+	IdempotencyRepository
 }
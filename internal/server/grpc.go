@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+type GRPCServer struct {
+	grpcServer      *grpc.Server
+	addr            string
+	notify          chan error
+	shutdownTimeout time.Duration
+}
+
+type GRPCConfig interface {
+	GetAddr() string
+	GetShutdownTimeout() time.Duration
+}
+
+// NewGRPC creates the gRPC server, register is called to add services to it
+func NewGRPC(register func(s *grpc.Server), cfg GRPCConfig) *GRPCServer {
+	grpcServer := grpc.NewServer()
+	register(grpcServer)
+
+	return &GRPCServer{
+		grpcServer:      grpcServer,
+		addr:            cfg.GetAddr(),
+		notify:          make(chan error, 1),
+		shutdownTimeout: cfg.GetShutdownTimeout(),
+	}
+}
+
+func (s *GRPCServer) Run() {
+	go func() {
+		listener, err := net.Listen("tcp", s.addr)
+		if err == nil {
+			err = s.grpcServer.Serve(listener)
+		}
+		s.notify <- err
+		close(s.notify)
+	}()
+}
+
+func (s *GRPCServer) Notify() <-chan error {
+	return s.notify
+}
+
+// Shutdown waits for in-flight calls to finish and cancels them after the shutdown timeout
+func (s *GRPCServer) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,135 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	"github.com/pintoter/warehouse-api/internal/repository"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/pintoter/warehouse-api/pkg/logger"
+)
+
+const defaultLimit = 50
+
+type Service struct {
+	repo      repository.ProductsRepository
+	txManager dbutil.TxManager
+}
+
+func NewService(repo repository.ProductsRepository, txManager dbutil.TxManager) service.CatalogService {
+	return &Service{
+		repo:      repo,
+		txManager: txManager,
+	}
+}
+
+func (s *Service) CreateProduct(r *http.Request, args *model.CreateProductReq, reply *model.CatalogProduct) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	product, err := s.repo.CreateProduct(ctx, normalizeProduct(model.CatalogProduct{Name: args.Name, Size: args.Size, Code: args.Code}))
+	if err != nil {
+		return productErr(ctx, err)
+	}
+
+	*reply = product
+	return nil
+}
+
+func (s *Service) UpdateProduct(r *http.Request, args *model.UpdateProductReq, reply *model.CatalogProduct) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	product, err := s.repo.UpdateProduct(ctx, normalizeProduct(model.CatalogProduct{ID: args.ID, Name: args.Name, Size: args.Size, Code: args.Code}))
+	if err != nil {
+		return productErr(ctx, err)
+	}
+
+	*reply = product
+	return nil
+}
+
+func (s *Service) GetProductByCode(r *http.Request, args *model.GetProductByCodeReq, reply *model.CatalogProduct) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	product, err := s.repo.GetProductByCode(ctx, strings.TrimSpace(args.Code))
+	if err != nil {
+		return productErr(ctx, err)
+	}
+
+	*reply = product
+	return nil
+}
+
+func (s *Service) ListProducts(r *http.Request, args *model.ListProductsReq, reply *model.ListProductsResp) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	filter := productFilter(args)
+
+	var resp model.ListProductsResp
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		resp.Products, err = s.repo.GetProducts(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		resp.Total, err = s.repo.GetProductsCount(ctx, filter)
+		return err
+	}, dbutil.WithIsolation(sql.LevelRepeatableRead), dbutil.WithReadOnly())
+	if err != nil {
+		return productErr(ctx, err)
+	}
+
+	if resp.Products == nil {
+		resp.Products = []model.CatalogProduct{}
+	}
+
+	*reply = resp
+	return nil
+}
+
+func normalizeProduct(product model.CatalogProduct) model.CatalogProduct {
+	product.Name = strings.TrimSpace(product.Name)
+	product.Size = strings.ToUpper(strings.TrimSpace(product.Size))
+	product.Code = strings.TrimSpace(product.Code)
+
+	return product
+}
+
+func productFilter(args *model.ListProductsReq) repoModel.ProductFilter {
+	filter := repoModel.ProductFilter{
+		Name:   strings.TrimSpace(args.Name),
+		Size:   strings.ToUpper(strings.TrimSpace(args.Size)),
+		Limit:  args.Limit,
+		Offset: args.Offset,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+
+	return filter
+}
+
+// productErr maps a repository error to a model error
+func productErr(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return model.ErrProductNotFound
+	case dbutil.IsUniqueViolation(err):
+		return model.ErrProductAlreadyExists
+	default:
+		logger.DebugKV(ctx, "Catalog", "err", err)
+		return model.ErrInternalServer
+	}
+}
@@ -0,0 +1,174 @@
+package catalog
+
+import (
+	"database/sql"
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type txConfig struct{}
+
+func (txConfig) GetMaxRetries() int {
+	return 0
+}
+
+func (txConfig) GetRetryBaseDelay() time.Duration {
+	return 0
+}
+
+func (txConfig) GetRetryMaxDelay() time.Duration {
+	return 0
+}
+
+func TestCreateProduct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func()
+
+	expectedQuery := "INSERT INTO product (name,size,code) VALUES ($1,$2,$3) RETURNING id, name, size, code"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.CreateProductReq
+		wantReply    model.CatalogProduct
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs("Puma Cap", "XL", "777").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}).AddRow(16, "Puma Cap", "XL", "777"))
+			},
+			args:      &model.CreateProductReq{Name: " Puma Cap", Size: "xl", Code: "777 "},
+			wantReply: model.CatalogProduct{ID: 16, Name: "Puma Cap", Size: "XL", Code: "777"},
+		},
+		{
+			name: "Duplicate code",
+			mockBehavior: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs("Lacoste T-Shirt", "XS", "12345").
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+			},
+			args:    &model.CreateProductReq{Name: "Lacoste T-Shirt", Size: "XS", Code: "12345"},
+			wantErr: model.ErrProductAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			var reply model.CatalogProduct
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.CreateProduct(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetProductByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, size, code FROM product WHERE code = $1")).
+		WithArgs("00000").
+		WillReturnError(sql.ErrNoRows)
+
+	var reply model.CatalogProduct
+	req := httptest.NewRequest("POST", "/rpc", nil)
+	err = s.GetProductByCode(req, &model.GetProductByCodeReq{Code: "00000"}, &reply)
+	assert.ErrorIs(t, err, model.ErrProductNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func()
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.ListProductsReq
+		wantReply    model.ListProductsResp
+		wantErr      error
+	}{
+		{
+			name: "Default limit",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, size, code FROM product WHERE (size = $1) ORDER BY id LIMIT 50 OFFSET 0")).
+					WithArgs("XXL").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}).AddRow(8, "Dads pants", "XXL", "1339"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM product WHERE (size = $1)")).
+					WithArgs("XXL").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectCommit()
+			},
+			args: &model.ListProductsReq{Size: "XXL"},
+			wantReply: model.ListProductsResp{
+				Products: []model.CatalogProduct{{ID: 8, Name: "Dads pants", Size: "XXL", Code: "1339"}},
+				Total:    1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			var reply model.ListProductsResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.ListProducts(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+// AdjustStock fixes the quantity of a product in a warehouse after a physical count
+// or an incident. CountedQuantity is the whole stock on shelves, including products
+// held by active reservations; Delta changes the available stock directly.
+func (s *Service) AdjustStock(r *http.Request, args *model.AdjustStockReq, reply *model.AdjustStockResp) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	code := strings.TrimSpace(args.Code)
+
+	var resp model.AdjustStockResp
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		_, err := s.repo.GetWarehouseAvailabilityById(ctx, args.WarehouseId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.ErrWarehouseNotFound
+			}
+			return repoErr(ctx, err)
+		}
+
+		product, err := s.repo.GetProductByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.ErrProductNotFound
+			}
+			return repoErr(ctx, err)
+		}
+
+		available, err := s.repo.GetWarehouseQuantity(ctx, args.WarehouseId, product.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return repoErr(ctx, err)
+		}
+
+		reserved, err := s.repo.GetReservedQuantity(ctx, args.WarehouseId, product.ID)
+		if err != nil {
+			return repoErr(ctx, err)
+		}
+
+		var delta int
+		if args.CountedQuantity != nil {
+			delta = *args.CountedQuantity - (available + reserved)
+		} else {
+			delta = *args.Delta
+		}
+
+		if available+delta < 0 {
+			return model.ErrAdjustmentBelowReserved
+		}
+
+		switch {
+		case delta > 0:
+			err = s.addQuantity(ctx, args.WarehouseId, product.ID, delta)
+			if err != nil {
+				return err
+			}
+		case delta < 0:
+			_, err = s.repo.SubtractWarehouseQuantity(ctx, args.WarehouseId, product.ID, -delta)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return model.ErrAdjustmentBelowReserved
+				}
+				return repoErr(ctx, err)
+			}
+		}
+
+		id, err := s.repo.CreateStockAdjustment(ctx, repoModel.StockAdjustment{
+			WarehouseId:     args.WarehouseId,
+			ProductId:       product.ID,
+			Reason:          args.Reason,
+			CountedQuantity: args.CountedQuantity,
+			Delta:           delta,
+		})
+		if err != nil {
+			return repoErr(ctx, err)
+		}
+
+		if delta != 0 {
+			err = s.recordMovement(ctx, args.WarehouseId, product.ID, delta, model.MovementAdjust, strconv.Itoa(id))
+			if err != nil {
+				return err
+			}
+		}
+
+		resp = model.AdjustStockResp{
+			AdjustmentId: id,
+			WarehouseId:  args.WarehouseId,
+			Code:         code,
+			Reason:       args.Reason,
+			Delta:        delta,
+			Quantity:     available + delta,
+			Reserved:     reserved,
+		}
+		return nil
+	})
+	if err != nil && (ctx.Err() != nil || dbutil.IsRetryable(err)) {
+		err = model.ErrInternalServer
+	}
+
+	if err != nil {
+		*reply = model.AdjustStockResp{}
+		return err
+	}
+
+	*reply = resp
+	return nil
+}
@@ -0,0 +1,166 @@
+package inventory
+
+import (
+	"database/sql"
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	warehouseQuantityQuery = "SELECT quantity FROM warehouse_product WHERE product_id = $1 AND warehouse_id = $2 FOR UPDATE"
+	reservedQuantityQuery  = "SELECT COALESCE(SUM(quantity), 0) FROM reservation"
+	adjustmentQuery        = "INSERT INTO stock_adjustment (warehouse_id,product_id,reason,counted_quantity,delta) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+)
+
+func TestAdjustStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func()
+
+	intPtr := func(v int) *int {
+		return &v
+	}
+
+	// Warehouse 1 holds 3 available products 12345 and 2 more are held by reservations
+	expectStock := func(available *int) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(availabilityQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"availability"}).AddRow(true))
+		mock.ExpectQuery(regexp.QuoteMeta(productQuery)).WithArgs("12345").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}).AddRow(1, "Lacoste T-Shirt", "XS", "12345"))
+		if available != nil {
+			mock.ExpectQuery(regexp.QuoteMeta(warehouseQuantityQuery)).WithArgs(1, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(*available))
+		} else {
+			mock.ExpectQuery(regexp.QuoteMeta(warehouseQuantityQuery)).WithArgs(1, 1).
+				WillReturnError(sql.ErrNoRows)
+		}
+		mock.ExpectQuery(regexp.QuoteMeta(reservedQuantityQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(2))
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.AdjustStockReq
+		wantReply    model.AdjustStockResp
+		wantErr      error
+	}{
+		{
+			name: "Count below stock",
+			mockBehavior: func() {
+				expectStock(intPtr(3))
+				mock.ExpectQuery(regexp.QuoteMeta(subtractQuantityQuery)).WithArgs(1, 1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(2))
+				mock.ExpectQuery(regexp.QuoteMeta(adjustmentQuery)).WithArgs(1, 1, model.AdjustmentCount, 4, -1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				mock.ExpectExec(regexp.QuoteMeta(movementQuery)).WithArgs(1, 1, -1, model.MovementAdjust, "10").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			args: &model.AdjustStockReq{WarehouseId: 1, Code: "12345", CountedQuantity: intPtr(4), Reason: model.AdjustmentCount},
+			wantReply: model.AdjustStockResp{
+				AdjustmentId: 10,
+				WarehouseId:  1,
+				Code:         "12345",
+				Reason:       model.AdjustmentCount,
+				Delta:        -1,
+				Quantity:     2,
+				Reserved:     2,
+			},
+		},
+		{
+			name: "Count matches stock",
+			mockBehavior: func() {
+				expectStock(intPtr(3))
+				mock.ExpectQuery(regexp.QuoteMeta(adjustmentQuery)).WithArgs(1, 1, model.AdjustmentCount, 5, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+				mock.ExpectCommit()
+			},
+			args: &model.AdjustStockReq{WarehouseId: 1, Code: "12345", CountedQuantity: intPtr(5), Reason: model.AdjustmentCount},
+			wantReply: model.AdjustStockResp{
+				AdjustmentId: 11,
+				WarehouseId:  1,
+				Code:         "12345",
+				Reason:       model.AdjustmentCount,
+				Quantity:     3,
+				Reserved:     2,
+			},
+		},
+		{
+			name: "Found product new to warehouse",
+			mockBehavior: func() {
+				expectStock(nil)
+				mock.ExpectQuery(regexp.QuoteMeta(addQuantityQuery)).WithArgs(1, 1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(1))
+				mock.ExpectQuery(regexp.QuoteMeta(adjustmentQuery)).WithArgs(1, 1, model.AdjustmentFound, nil, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+				mock.ExpectExec(regexp.QuoteMeta(movementQuery)).WithArgs(1, 1, 1, model.MovementAdjust, "12").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+			args: &model.AdjustStockReq{WarehouseId: 1, Code: "12345", Delta: intPtr(1), Reason: model.AdjustmentFound},
+			wantReply: model.AdjustStockResp{
+				AdjustmentId: 12,
+				WarehouseId:  1,
+				Code:         "12345",
+				Reason:       model.AdjustmentFound,
+				Delta:        1,
+				Quantity:     1,
+				Reserved:     2,
+			},
+		},
+		{
+			name: "Count below reserved",
+			mockBehavior: func() {
+				expectStock(intPtr(3))
+				mock.ExpectRollback()
+			},
+			args:    &model.AdjustStockReq{WarehouseId: 1, Code: "12345", CountedQuantity: intPtr(1), Reason: model.AdjustmentCount},
+			wantErr: model.ErrAdjustmentBelowReserved,
+		},
+		{
+			name: "Lost more than available",
+			mockBehavior: func() {
+				expectStock(intPtr(3))
+				mock.ExpectRollback()
+			},
+			args:    &model.AdjustStockReq{WarehouseId: 1, Code: "12345", Delta: intPtr(-4), Reason: model.AdjustmentLost},
+			wantErr: model.ErrAdjustmentBelowReserved,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			var reply model.AdjustStockResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.AdjustStock(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
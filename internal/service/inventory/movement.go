@@ -0,0 +1,62 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+const defaultMovementsLimit = 100
+
+// GetStockMovements returns the stock ledger ordered by time.
+// From is inclusive and To is exclusive.
+func (s *Service) GetStockMovements(r *http.Request, args *model.GetStockMovementsReq, reply *model.GetStockMovementsResp) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	filter := movementsFilter(args)
+
+	var movements []model.StockMovement
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		movements, err = s.repo.GetStockMovements(ctx, filter)
+		if err != nil {
+			return repoErr(ctx, err)
+		}
+
+		return nil
+	}, dbutil.WithIsolation(sql.LevelReadCommitted), dbutil.WithReadOnly())
+	if err != nil {
+		return model.ErrInternalServer
+	}
+
+	if movements == nil {
+		movements = []model.StockMovement{}
+	}
+
+	*reply = model.GetStockMovementsResp{Movements: movements}
+	return nil
+}
+
+func movementsFilter(args *model.GetStockMovementsReq) repoModel.StockMovementFilter {
+	filter := repoModel.StockMovementFilter{
+		Code:        strings.TrimSpace(args.Code),
+		WarehouseId: args.WarehouseId,
+		From:        args.From,
+		To:          args.To,
+		Limit:       args.Limit,
+		Offset:      args.Offset,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultMovementsLimit
+	}
+
+	return filter
+}
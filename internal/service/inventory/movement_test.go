@@ -0,0 +1,86 @@
+package inventory
+
+import (
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStockMovements(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func()
+
+	from := time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "warehouse_id", "code", "delta", "reason", "reference_id", "created_at"}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.GetStockMovementsReq
+		wantReply    model.GetStockMovementsResp
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("FROM stock_movement sm JOIN product p ON p.id = sm.product_id WHERE (sm.warehouse_id = $1) ORDER BY sm.created_at, sm.id LIMIT 100 OFFSET 0")).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 2, "12345", -3, "reserve", "422ab5fa-fbf1-461a-99dc-2c6a49c323f1", from))
+				mock.ExpectCommit()
+			},
+			args: &model.GetStockMovementsReq{WarehouseId: 2},
+			wantReply: model.GetStockMovementsResp{
+				Movements: []model.StockMovement{
+					{ID: 1, WarehouseId: 2, Code: "12345", Delta: -3, Reason: model.MovementReserve, ReferenceId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1", CreatedAt: from},
+				},
+			},
+		},
+		{
+			name: "Empty ledger",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("FROM stock_movement sm")).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectCommit()
+			},
+			args:      &model.GetStockMovementsReq{},
+			wantReply: model.GetStockMovementsResp{Movements: []model.StockMovement{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			var reply model.GetStockMovementsResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.GetStockMovements(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
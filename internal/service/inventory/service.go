@@ -0,0 +1,157 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	"github.com/pintoter/warehouse-api/internal/repository"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/pintoter/warehouse-api/pkg/logger"
+)
+
+type Service struct {
+	repo      repository.Repository
+	txManager dbutil.TxManager
+}
+
+func NewService(repo repository.Repository, txManager dbutil.TxManager) service.InventoryService {
+	return &Service{
+		repo:      repo,
+		txManager: txManager,
+	}
+}
+
+// ReceiveStock books incoming goods into the warehouse and stores the receipt for audit.
+// The whole receipt is applied in one transaction.
+func (s *Service) ReceiveStock(r *http.Request, args *model.ReceiveStockReq, reply *model.ReceiveStockResp) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	supplierRef := strings.TrimSpace(args.SupplierRef)
+	lines := mergeLines(args.Lines)
+
+	var resp model.ReceiveStockResp
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		resp = model.ReceiveStockResp{
+			SupplierRef: supplierRef,
+			WarehouseId: args.WarehouseId,
+			Lines:       make([]model.ReceivedLineResp, 0, len(lines)),
+		}
+
+		_, err := s.repo.GetWarehouseAvailabilityById(ctx, args.WarehouseId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.ErrWarehouseNotFound
+			}
+			return repoErr(ctx, err)
+		}
+
+		resp.ReceiptId, resp.ReceivedAt, err = s.repo.CreateReceipt(ctx, supplierRef, args.WarehouseId)
+		if err != nil {
+			return repoErr(ctx, err)
+		}
+
+		for _, line := range lines {
+			product, err := s.repo.GetProductByCode(ctx, line.Code)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("%w: %s", model.ErrProductNotFound, line.Code)
+				}
+				return repoErr(ctx, err)
+			}
+
+			err = s.repo.CreateReceiptLine(ctx, resp.ReceiptId, product.ID, line.Quantity)
+			if err != nil {
+				return repoErr(ctx, err)
+			}
+
+			total, err := s.repo.AddWarehouseQuantity(ctx, args.WarehouseId, product.ID, line.Quantity)
+			if err != nil {
+				if dbutil.IsNumericOutOfRange(err) {
+					return fmt.Errorf("%w: %s", model.ErrQuantityOverflow, line.Code)
+				}
+				return repoErr(ctx, err)
+			}
+
+			err = s.recordMovement(ctx, args.WarehouseId, product.ID, line.Quantity, model.MovementReceive, strconv.Itoa(resp.ReceiptId))
+			if err != nil {
+				return err
+			}
+
+			resp.Lines = append(resp.Lines, model.ReceivedLineResp{
+				Code:              line.Code,
+				Quantity:          line.Quantity,
+				WarehouseQuantity: total,
+			})
+		}
+
+		return nil
+	})
+	if err != nil && (ctx.Err() != nil || dbutil.IsRetryable(err)) {
+		err = model.ErrInternalServer
+	}
+
+	if err != nil {
+		*reply = model.ReceiveStockResp{}
+		return err
+	}
+
+	*reply = resp
+	return nil
+}
+
+// mergeLines merges lines with the same code.
+// Lines are sorted by code so concurrent receipts lock warehouse_product rows in the same order.
+func mergeLines(receiptLines []model.ReceiveStockLineReq) []model.ReceiveStockLineReq {
+	quantities := make(map[string]int, len(receiptLines))
+	for _, line := range receiptLines {
+		quantities[strings.TrimSpace(line.Code)] += line.Quantity
+	}
+
+	lines := make([]model.ReceiveStockLineReq, 0, len(quantities))
+	for code, quantity := range quantities {
+		lines = append(lines, model.ReceiveStockLineReq{Code: code, Quantity: quantity})
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].Code < lines[j].Code
+	})
+
+	return lines
+}
+
+// recordMovement writes the quantity change to the stock ledger
+func (s *Service) recordMovement(ctx context.Context, warehouseId, productId, delta int, reason model.MovementReason, referenceId string) error {
+	err := s.repo.CreateStockMovement(ctx, repoModel.StockMovement{
+		WarehouseId: warehouseId,
+		ProductId:   productId,
+		Delta:       delta,
+		Reason:      reason,
+		ReferenceId: referenceId,
+	})
+	if err != nil {
+		return repoErr(ctx, err)
+	}
+
+	return nil
+}
+
+// repoErr keeps retryable errors for the transaction manager and hides the rest behind ErrInternalServer
+func repoErr(ctx context.Context, err error) error {
+	if dbutil.IsRetryable(err) {
+		return err
+	}
+
+	logger.DebugKV(ctx, "Inventory", "err", err)
+	return model.ErrInternalServer
+}
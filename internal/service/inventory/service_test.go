@@ -0,0 +1,190 @@
+package inventory
+
+import (
+	"database/sql"
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type txConfig struct{}
+
+func (txConfig) GetMaxRetries() int {
+	return 0
+}
+
+func (txConfig) GetRetryBaseDelay() time.Duration {
+	return 0
+}
+
+func (txConfig) GetRetryMaxDelay() time.Duration {
+	return 0
+}
+
+const (
+	availabilityQuery = "SELECT availability FROM warehouse WHERE id = $1"
+	receiptQuery      = "INSERT INTO receipt (supplier_ref,warehouse_id) VALUES ($1,$2) RETURNING id, received_at"
+	productQuery      = "SELECT id, name, size, code FROM product WHERE code = $1"
+	receiptLineQuery  = "INSERT INTO receipt_line (receipt_id,product_id,quantity) VALUES ($1,$2,$3)"
+	addQuantityQuery  = "INSERT INTO warehouse_product (warehouse_id,product_id,quantity) VALUES ($1,$2,$3) ON CONFLICT"
+	movementQuery     = "INSERT INTO stock_movement (warehouse_id,product_id,delta,reason,reference_id) VALUES ($1,$2,$3,$4,$5)"
+)
+
+func TestReceiveStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func()
+
+	receivedAt := time.Date(2024, 3, 25, 10, 0, 0, 0, time.UTC)
+	productRows := func(id int, code string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "size", "code"}).AddRow(id, "Lacoste T-Shirt", "XS", code)
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.ReceiveStockReq
+		wantReply    model.ReceiveStockResp
+		wantErr      error
+	}{
+		{
+			name: "Success with merged lines",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(availabilityQuery)).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"availability"}).AddRow(false))
+				mock.ExpectQuery(regexp.QuoteMeta(receiptQuery)).WithArgs("INV-1", 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "received_at"}).AddRow(7, receivedAt))
+				mock.ExpectQuery(regexp.QuoteMeta(productQuery)).WithArgs("12345").
+					WillReturnRows(productRows(1, "12345"))
+				mock.ExpectExec(regexp.QuoteMeta(receiptLineQuery)).WithArgs(7, 1, 5).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(addQuantityQuery)).WithArgs(3, 1, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(8))
+				mock.ExpectExec(regexp.QuoteMeta(movementQuery)).WithArgs(3, 1, 5, model.MovementReceive, "7").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(productQuery)).WithArgs("12346").
+					WillReturnRows(productRows(2, "12346"))
+				mock.ExpectExec(regexp.QuoteMeta(receiptLineQuery)).WithArgs(7, 2, 1).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(addQuantityQuery)).WithArgs(3, 2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(movementQuery)).WithArgs(3, 2, 1, model.MovementReceive, "7").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+			args: &model.ReceiveStockReq{
+				SupplierRef: "INV-1",
+				WarehouseId: 3,
+				Lines: []model.ReceiveStockLineReq{
+					{Code: "12346", Quantity: 1},
+					{Code: "12345", Quantity: 2},
+					{Code: "12345", Quantity: 3},
+				},
+			},
+			wantReply: model.ReceiveStockResp{
+				ReceiptId:   7,
+				SupplierRef: "INV-1",
+				WarehouseId: 3,
+				ReceivedAt:  receivedAt,
+				Lines: []model.ReceivedLineResp{
+					{Code: "12345", Quantity: 5, WarehouseQuantity: 8},
+					{Code: "12346", Quantity: 1, WarehouseQuantity: 1},
+				},
+			},
+		},
+		{
+			name: "Unknown warehouse",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(availabilityQuery)).WithArgs(100).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			args: &model.ReceiveStockReq{
+				SupplierRef: "INV-1",
+				WarehouseId: 100,
+				Lines:       []model.ReceiveStockLineReq{{Code: "12345", Quantity: 1}},
+			},
+			wantErr: model.ErrWarehouseNotFound,
+		},
+		{
+			name: "Unknown product",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(availabilityQuery)).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"availability"}).AddRow(true))
+				mock.ExpectQuery(regexp.QuoteMeta(receiptQuery)).WithArgs("INV-1", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "received_at"}).AddRow(8, receivedAt))
+				mock.ExpectQuery(regexp.QuoteMeta(productQuery)).WithArgs("00000").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			args: &model.ReceiveStockReq{
+				SupplierRef: "INV-1",
+				WarehouseId: 1,
+				Lines:       []model.ReceiveStockLineReq{{Code: "00000", Quantity: 1}},
+			},
+			wantErr: model.ErrProductNotFound,
+		},
+		{
+			name: "Quantity overflow",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(availabilityQuery)).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"availability"}).AddRow(true))
+				mock.ExpectQuery(regexp.QuoteMeta(receiptQuery)).WithArgs("INV-1", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "received_at"}).AddRow(9, receivedAt))
+				mock.ExpectQuery(regexp.QuoteMeta(productQuery)).WithArgs("12345").
+					WillReturnRows(productRows(1, "12345"))
+				mock.ExpectExec(regexp.QuoteMeta(receiptLineQuery)).WithArgs(9, 1, 40000).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(addQuantityQuery)).WithArgs(1, 1, 40000).
+					WillReturnError(&pgconn.PgError{Code: "22003"})
+				mock.ExpectRollback()
+			},
+			args: &model.ReceiveStockReq{
+				SupplierRef: "INV-1",
+				WarehouseId: 1,
+				Lines:       []model.ReceiveStockLineReq{{Code: "12345", Quantity: 40000}},
+			},
+			wantErr: model.ErrQuantityOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			var reply model.ReceiveStockResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.ReceiveStock(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, model.ReceiveStockResp{}, reply)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
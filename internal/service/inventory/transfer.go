@@ -0,0 +1,177 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+// TransferStock moves stock of a product between warehouses in one transaction.
+// With InTransit the stock only leaves the source warehouse and arrives on ReceiveTransfer.
+func (s *Service) TransferStock(r *http.Request, args *model.TransferStockReq, reply *model.Transfer) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	code := strings.TrimSpace(args.Code)
+
+	var transfer repoModel.Transfer
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		for _, warehouseId := range []int{args.FromWarehouseId, args.ToWarehouseId} {
+			_, err := s.repo.GetWarehouseAvailabilityById(ctx, warehouseId)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return model.ErrWarehouseNotFound
+				}
+				return repoErr(ctx, err)
+			}
+		}
+
+		product, err := s.repo.GetProductByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.ErrProductNotFound
+			}
+			return repoErr(ctx, err)
+		}
+
+		_, err = s.repo.SubtractWarehouseQuantity(ctx, args.FromWarehouseId, product.ID, args.Quantity)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.ErrInsufficientStock
+			}
+			return repoErr(ctx, err)
+		}
+
+		status := model.TransferShipped
+		if !args.InTransit {
+			err = s.addQuantity(ctx, args.ToWarehouseId, product.ID, args.Quantity)
+			if err != nil {
+				return err
+			}
+			status = model.TransferReceived
+		}
+
+		id, err := s.repo.CreateTransfer(ctx, product.ID, args.FromWarehouseId, args.ToWarehouseId, args.Quantity, status)
+		if err != nil {
+			return repoErr(ctx, err)
+		}
+
+		err = s.recordMovement(ctx, args.FromWarehouseId, product.ID, -args.Quantity, model.MovementTransferOut, strconv.Itoa(id))
+		if err != nil {
+			return err
+		}
+
+		if status == model.TransferReceived {
+			err = s.recordMovement(ctx, args.ToWarehouseId, product.ID, args.Quantity, model.MovementTransferIn, strconv.Itoa(id))
+			if err != nil {
+				return err
+			}
+		}
+
+		transfer, err = s.repo.GetTransferById(ctx, id)
+		if err != nil {
+			return repoErr(ctx, err)
+		}
+
+		return nil
+	})
+	if err != nil && (ctx.Err() != nil || dbutil.IsRetryable(err)) {
+		err = model.ErrInternalServer
+	}
+
+	if err != nil {
+		*reply = model.Transfer{}
+		return err
+	}
+
+	*reply = toTransfer(transfer)
+	return nil
+}
+
+// ReceiveTransfer books in-transit stock into the destination warehouse
+func (s *Service) ReceiveTransfer(r *http.Request, args *model.ReceiveTransferReq, reply *model.Transfer) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var transfer repoModel.Transfer
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		transfer, err = s.repo.GetTransferById(ctx, args.TransferId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.ErrTransferNotFound
+			}
+			return repoErr(ctx, err)
+		}
+
+		if transfer.Status == model.TransferReceived {
+			return model.ErrTransferReceived
+		}
+
+		err = s.addQuantity(ctx, transfer.ToWarehouseId, transfer.ProductId, transfer.Quantity)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordMovement(ctx, transfer.ToWarehouseId, transfer.ProductId, transfer.Quantity, model.MovementTransferIn, strconv.Itoa(transfer.ID))
+		if err != nil {
+			return err
+		}
+
+		err = s.repo.UpdateTransferStatus(ctx, transfer.ID, model.TransferReceived)
+		if err != nil {
+			return repoErr(ctx, err)
+		}
+
+		transfer, err = s.repo.GetTransferById(ctx, transfer.ID)
+		if err != nil {
+			return repoErr(ctx, err)
+		}
+
+		return nil
+	})
+	if err != nil && (ctx.Err() != nil || dbutil.IsRetryable(err)) {
+		err = model.ErrInternalServer
+	}
+
+	if err != nil {
+		*reply = model.Transfer{}
+		return err
+	}
+
+	*reply = toTransfer(transfer)
+	return nil
+}
+
+func (s *Service) addQuantity(ctx context.Context, warehouseId, productId, quantity int) error {
+	_, err := s.repo.AddWarehouseQuantity(ctx, warehouseId, productId, quantity)
+	if err != nil {
+		if dbutil.IsNumericOutOfRange(err) {
+			return model.ErrQuantityOverflow
+		}
+		return repoErr(ctx, err)
+	}
+
+	return nil
+}
+
+func toTransfer(t repoModel.Transfer) model.Transfer {
+	return model.Transfer{
+		ID:              t.ID,
+		Code:            t.Code,
+		FromWarehouseId: t.FromWarehouseId,
+		ToWarehouseId:   t.ToWarehouseId,
+		Quantity:        t.Quantity,
+		Status:          t.Status,
+		ShippedAt:       t.ShippedAt,
+		ReceivedAt:      t.ReceivedAt,
+	}
+}
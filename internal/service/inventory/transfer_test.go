@@ -0,0 +1,257 @@
+package inventory
+
+import (
+	"database/sql"
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	subtractQuantityQuery = "UPDATE warehouse_product SET quantity = quantity - $1"
+	createTransferQuery   = "INSERT INTO transfer"
+	getTransferQuery      = "FROM transfer t JOIN product p ON p.id = t.product_id WHERE t.id = $1 FOR UPDATE OF t"
+	updateTransferQuery   = "UPDATE transfer SET status = $1, received_at = CURRENT_TIMESTAMP WHERE id = $2"
+)
+
+var transferColumns = []string{"id", "product_id", "code", "from_warehouse_id", "to_warehouse_id", "quantity", "status", "shipped_at", "received_at"}
+
+func TestTransferStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func(args *model.TransferStockReq)
+
+	shippedAt := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	expectWarehouses := func(args *model.TransferStockReq) {
+		mock.ExpectQuery(regexp.QuoteMeta(availabilityQuery)).WithArgs(args.FromWarehouseId).
+			WillReturnRows(sqlmock.NewRows([]string{"availability"}).AddRow(false))
+		mock.ExpectQuery(regexp.QuoteMeta(availabilityQuery)).WithArgs(args.ToWarehouseId).
+			WillReturnRows(sqlmock.NewRows([]string{"availability"}).AddRow(true))
+	}
+	expectProduct := func(args *model.TransferStockReq) {
+		mock.ExpectQuery(regexp.QuoteMeta(productQuery)).WithArgs(args.Code).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size", "code"}).AddRow(9, "Adidas Hoodie", "L", args.Code))
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.TransferStockReq
+		wantReply    model.Transfer
+		wantErr      error
+	}{
+		{
+			name: "Immediate transfer",
+			mockBehavior: func(args *model.TransferStockReq) {
+				mock.ExpectBegin()
+				expectWarehouses(args)
+				expectProduct(args)
+				mock.ExpectQuery(regexp.QuoteMeta(subtractQuantityQuery)).WithArgs(args.Quantity, args.FromWarehouseId, 9).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(1))
+				mock.ExpectQuery(regexp.QuoteMeta(addQuantityQuery)).WithArgs(args.ToWarehouseId, 9, args.Quantity).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(2))
+				mock.ExpectQuery(regexp.QuoteMeta(createTransferQuery)).
+					WithArgs(9, args.FromWarehouseId, args.ToWarehouseId, args.Quantity, model.TransferReceived).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(movementQuery)).WithArgs(args.FromWarehouseId, 9, -args.Quantity, model.MovementTransferOut, "1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(movementQuery)).WithArgs(args.ToWarehouseId, 9, args.Quantity, model.MovementTransferIn, "1").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(getTransferQuery)).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(transferColumns).AddRow(1, 9, args.Code, 3, 1, 2, "received", shippedAt, shippedAt))
+				mock.ExpectCommit()
+			},
+			args: &model.TransferStockReq{Code: "10101011", FromWarehouseId: 3, ToWarehouseId: 1, Quantity: 2},
+			wantReply: model.Transfer{
+				ID:              1,
+				Code:            "10101011",
+				FromWarehouseId: 3,
+				ToWarehouseId:   1,
+				Quantity:        2,
+				Status:          model.TransferReceived,
+				ShippedAt:       shippedAt,
+				ReceivedAt:      &shippedAt,
+			},
+		},
+		{
+			name: "In transit",
+			mockBehavior: func(args *model.TransferStockReq) {
+				mock.ExpectBegin()
+				expectWarehouses(args)
+				expectProduct(args)
+				mock.ExpectQuery(regexp.QuoteMeta(subtractQuantityQuery)).WithArgs(args.Quantity, args.FromWarehouseId, 9).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(0))
+				mock.ExpectQuery(regexp.QuoteMeta(createTransferQuery)).
+					WithArgs(9, args.FromWarehouseId, args.ToWarehouseId, args.Quantity, model.TransferShipped).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec(regexp.QuoteMeta(movementQuery)).WithArgs(args.FromWarehouseId, 9, -args.Quantity, model.MovementTransferOut, "2").
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(getTransferQuery)).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(transferColumns).AddRow(2, 9, args.Code, 3, 1, 3, "shipped", shippedAt, nil))
+				mock.ExpectCommit()
+			},
+			args: &model.TransferStockReq{Code: "10101011", FromWarehouseId: 3, ToWarehouseId: 1, Quantity: 3, InTransit: true},
+			wantReply: model.Transfer{
+				ID:              2,
+				Code:            "10101011",
+				FromWarehouseId: 3,
+				ToWarehouseId:   1,
+				Quantity:        3,
+				Status:          model.TransferShipped,
+				ShippedAt:       shippedAt,
+			},
+		},
+		{
+			name: "Not enough products",
+			mockBehavior: func(args *model.TransferStockReq) {
+				mock.ExpectBegin()
+				expectWarehouses(args)
+				expectProduct(args)
+				mock.ExpectQuery(regexp.QuoteMeta(subtractQuantityQuery)).WithArgs(args.Quantity, args.FromWarehouseId, 9).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			args:    &model.TransferStockReq{Code: "10101011", FromWarehouseId: 3, ToWarehouseId: 1, Quantity: 100},
+			wantErr: model.ErrInsufficientStock,
+		},
+		{
+			name: "Unknown warehouse",
+			mockBehavior: func(args *model.TransferStockReq) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(availabilityQuery)).WithArgs(args.FromWarehouseId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			args:    &model.TransferStockReq{Code: "10101011", FromWarehouseId: 100, ToWarehouseId: 1, Quantity: 1},
+			wantErr: model.ErrWarehouseNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			var reply model.Transfer
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.TransferStock(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, model.Transfer{}, reply)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReceiveTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func(args *model.ReceiveTransferReq)
+
+	shippedAt := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	receivedAt := shippedAt.Add(2 * time.Hour)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.ReceiveTransferReq
+		wantReply    model.Transfer
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args *model.ReceiveTransferReq) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(getTransferQuery)).WithArgs(args.TransferId).
+					WillReturnRows(sqlmock.NewRows(transferColumns).AddRow(2, 9, "10101011", 3, 1, 3, "shipped", shippedAt, nil))
+				mock.ExpectQuery(regexp.QuoteMeta(addQuantityQuery)).WithArgs(1, 9, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(3))
+				mock.ExpectExec(regexp.QuoteMeta(movementQuery)).WithArgs(1, 9, 3, model.MovementTransferIn, "2").
+					WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectExec(regexp.QuoteMeta(updateTransferQuery)).WithArgs(model.TransferReceived, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(getTransferQuery)).WithArgs(args.TransferId).
+					WillReturnRows(sqlmock.NewRows(transferColumns).AddRow(2, 9, "10101011", 3, 1, 3, "received", shippedAt, receivedAt))
+				mock.ExpectCommit()
+			},
+			args: &model.ReceiveTransferReq{TransferId: 2},
+			wantReply: model.Transfer{
+				ID:              2,
+				Code:            "10101011",
+				FromWarehouseId: 3,
+				ToWarehouseId:   1,
+				Quantity:        3,
+				Status:          model.TransferReceived,
+				ShippedAt:       shippedAt,
+				ReceivedAt:      &receivedAt,
+			},
+		},
+		{
+			name: "Already received",
+			mockBehavior: func(args *model.ReceiveTransferReq) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(getTransferQuery)).WithArgs(args.TransferId).
+					WillReturnRows(sqlmock.NewRows(transferColumns).AddRow(1, 9, "10101011", 3, 1, 2, "received", shippedAt, shippedAt))
+				mock.ExpectRollback()
+			},
+			args:    &model.ReceiveTransferReq{TransferId: 1},
+			wantErr: model.ErrTransferReceived,
+		},
+		{
+			name: "Not found",
+			mockBehavior: func(args *model.ReceiveTransferReq) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(getTransferQuery)).WithArgs(args.TransferId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			args:    &model.ReceiveTransferReq{TransferId: 100},
+			wantErr: model.ErrTransferNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			var reply model.Transfer
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.ReceiveTransfer(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
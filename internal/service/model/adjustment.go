@@ -0,0 +1,19 @@
+package model
+
+type AdjustmentReason string
+
+const (
+	AdjustmentDamaged AdjustmentReason = "damaged"
+	AdjustmentLost    AdjustmentReason = "lost"
+	AdjustmentFound   AdjustmentReason = "found"
+	AdjustmentCount   AdjustmentReason = "count"
+)
+
+func (r AdjustmentReason) IsValid() bool {
+	switch r {
+	case AdjustmentDamaged, AdjustmentLost, AdjustmentFound, AdjustmentCount:
+		return true
+	default:
+		return false
+	}
+}
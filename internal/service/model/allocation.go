@@ -0,0 +1,21 @@
+package model
+
+// AllocationStrategyName selects how a reservation line is spread over warehouses
+type AllocationStrategyName string
+
+const (
+	StrategyLargestStockFirst AllocationStrategyName = "largest_stock_first"
+	StrategyFewestWarehouses  AllocationStrategyName = "fewest_warehouses"
+	StrategyWarehousePriority AllocationStrategyName = "warehouse_priority"
+	StrategySingleWarehouse   AllocationStrategyName = "single_warehouse"
+	StrategyNearest           AllocationStrategyName = "nearest"
+)
+
+func (n AllocationStrategyName) IsValid() bool {
+	switch n {
+	case StrategyLargestStockFirst, StrategyFewestWarehouses, StrategyWarehousePriority, StrategySingleWarehouse, StrategyNearest:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+// ProductSizes lists values of the GOOD_SIZE enum
+var ProductSizes = []string{"XS", "S", "M", "L", "XL", "XXL", "XXXL"}
+
+type CatalogProduct struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Size string `json:"size"`
+	Code string `json:"code"`
+}
+
+func IsValidProductSize(size string) bool {
+	for _, s := range ProductSizes {
+		if s == size {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+package model
+
+import "errors"
+
+// ErrorCode is a stable machine-readable error class returned to clients
+type ErrorCode string
+
+const (
+	CodeInvalidInput      ErrorCode = "INVALID_INPUT"
+	CodeNotFound          ErrorCode = "NOT_FOUND"
+	CodeInsufficientStock ErrorCode = "INSUFFICIENT_STOCK"
+	CodeConflict          ErrorCode = "CONFLICT"
+	CodeInternal          ErrorCode = "INTERNAL"
+)
+
+var errorCodes = map[error]ErrorCode{
+	ErrInvalidInput:               CodeInvalidInput,
+	ErrInvalidCode:                CodeInvalidInput,
+	ErrInvalidReservationQuantity: CodeInvalidInput,
+	ErrInvalidStrategy:            CodeInvalidInput,
+	ErrInvalidLocation:            CodeInvalidInput,
+	ErrInvalidDestination:         CodeInvalidInput,
+	ErrValidation:                 CodeInvalidInput,
+
+	ErrReservationNotFound: CodeNotFound,
+	ErrWarehouseNotFound:   CodeNotFound,
+	ErrProductNotFound:     CodeNotFound,
+	ErrTransferNotFound:    CodeNotFound,
+
+	ErrInvalidQuantity:            CodeInsufficientStock,
+	ErrInsufficientStock:          CodeInsufficientStock,
+	ErrNoSingleWarehouse:          CodeInsufficientStock,
+	ErrInsufficientWarehouseStock: CodeInsufficientStock,
+	ErrAdjustmentBelowReserved:    CodeInsufficientStock,
+
+	ErrFailedReservation:        CodeConflict,
+	ErrReservationCommitted:     CodeConflict,
+	ErrInvalidReservationStatus: CodeConflict,
+	ErrIdempotencyKeyReused:     CodeConflict,
+	ErrIdempotencyKeyInProgress: CodeConflict,
+	ErrWarehouseAlreadyExists:   CodeConflict,
+	ErrProductAlreadyExists:     CodeConflict,
+	ErrTransferReceived:         CodeConflict,
+	ErrWarehouseUnavailable:     CodeConflict,
+	ErrQuantityOverflow:         CodeConflict,
+
+	ErrInternalServer: CodeInternal,
+}
+
+// Error is the structured form of a failed operation. Details carry values the client
+// may act on, e.g. the available quantity when stock is insufficient.
+type Error struct {
+	Code    ErrorCode              `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+
+	cause error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// CodeOf returns the code of the first known error in the chain, CodeInternal for unknown errors
+func CodeOf(err error) ErrorCode {
+	var modelErr *Error
+	if errors.As(err, &modelErr) && modelErr.Code != "" {
+		return modelErr.Code
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		if code, ok := errorCodes[err]; ok {
+			return code
+		}
+	}
+
+	return CodeInternal
+}
+
+// WithDetails attaches details to err keeping it comparable with errors.Is
+func WithDetails(err error, details map[string]interface{}) error {
+	return &Error{
+		Code:    CodeOf(err),
+		Message: err.Error(),
+		Details: details,
+		cause:   err,
+	}
+}
+
+// NewError converts err to the structured form returned to clients
+func NewError(err error) *Error {
+	var modelErr *Error
+	if errors.As(err, &modelErr) {
+		return &Error{Code: modelErr.Code, Message: err.Error(), Details: modelErr.Details, cause: err}
+	}
+
+	return &Error{Code: CodeOf(err), Message: err.Error(), cause: err}
+}
@@ -9,4 +9,25 @@ var (
 	ErrInvalidReservationQuantity = errors.New("too many products for release")
 	ErrInternalServer             = errors.New("internal server error, try later")
 	ErrFailedReservation          = errors.New("failed to reserve item from warehouse")
+	ErrReservationNotFound        = errors.New("reservation not found")
+	ErrReservationCommitted       = errors.New("reservation is already committed")
+	ErrInvalidReservationStatus   = errors.New("operation is not allowed for reservation in current status")
+	ErrIdempotencyKeyReused       = errors.New("idempotency key is already used with another request")
+	ErrIdempotencyKeyInProgress   = errors.New("request with this idempotency key is still in progress")
+	ErrWarehouseAlreadyExists     = errors.New("warehouse with this name already exists")
+	ErrWarehouseNotFound          = errors.New("warehouse not found")
+	ErrProductAlreadyExists       = errors.New("product with this code already exists")
+	ErrProductNotFound            = errors.New("product not found")
+	ErrQuantityOverflow           = errors.New("quantity of product in warehouse exceeds the limit")
+	ErrInsufficientStock          = errors.New("not enough products in source warehouse")
+	ErrTransferNotFound           = errors.New("transfer not found")
+	ErrTransferReceived           = errors.New("transfer is already received")
+	ErrAdjustmentBelowReserved    = errors.New("adjustment would leave less stock than held by active reservations")
+	ErrInvalidStrategy            = errors.New("strategy must be one of largest_stock_first, fewest_warehouses, warehouse_priority, single_warehouse, nearest")
+	ErrNoSingleWarehouse          = errors.New("no single warehouse holds the requested quantity")
+	ErrInvalidLocation            = errors.New("latitude must be from -90 to 90 and longitude from -180 to 180")
+	ErrInvalidDestination         = errors.New("nearest strategy requires destination or destination_region")
+	ErrWarehouseUnavailable       = errors.New("warehouse is unavailable")
+	ErrInsufficientWarehouseStock = errors.New("not enough products in the requested warehouses")
+	ErrValidation                 = errors.New("request has invalid fields")
 )
@@ -1,17 +1,40 @@
 package model
 
+import "time"
+
+const (
+	// MaxIdempotencyKeyLength is the limit in bytes of idempotency_key, the key is stored in a varchar(255) column
+	MaxIdempotencyKeyLength = 255
+	// MaxTTLSeconds limits ttl_seconds to 30 days, larger values would overflow the expiry interval
+	MaxTTLSeconds = 30 * 24 * 60 * 60
+)
+
 type ReserveProductReq struct {
-	Code     string `json:"code"`
-	Quantity int    `json:"quantity"`
+	Code         string `json:"code"`
+	Quantity     int    `json:"quantity"`
+	WarehouseId  int    `json:"warehouse_id,omitempty"`
+	WarehouseIds []int  `json:"warehouse_ids,omitempty"`
 }
 
 type ReserveProductsReq struct {
-	Products []ReserveProductReq `json:"products"`
+	Products          []ReserveProductReq    `json:"products"`
+	Atomic            bool                   `json:"atomic"`
+	TTLSeconds        int                    `json:"ttl_seconds"`
+	IdempotencyKey    string                 `json:"idempotency_key"`
+	AllowPartial      bool                   `json:"allow_partial"`
+	Strategy          AllocationStrategyName `json:"strategy"`
+	WarehousePriority []int                  `json:"warehouse_priority"`
+	Destination       *GeoPoint              `json:"destination"`
+	DestinationRegion string                 `json:"destination_region"`
 }
 
 type ReserveProductResp struct {
-	Code   string `json:"code"`
-	Status string `json:"status"`
+	Code      string `json:"code"`
+	Status    string `json:"status"`
+	Requested int    `json:"requested"`
+	Reserved  int    `json:"reserved"`
+	Available *int   `json:"available,omitempty"`
+	Error     *Error `json:"error,omitempty"`
 }
 
 type ReserveProductsResp struct {
@@ -26,19 +49,180 @@ type ReleaseProductReq struct {
 }
 
 type ReleaseProductsReq struct {
-	Products []ReleaseProductReq `json:"products"`
+	Products       []ReleaseProductReq `json:"products"`
+	IdempotencyKey string              `json:"idempotency_key"`
 }
 
 type ReleaseProductResp struct {
 	ReservationId string `json:"reservation_id"`
 	Code          string `json:"code"`
 	Status        string `json:"status"`
+	Error         *Error `json:"error,omitempty"`
 }
 
 type ReleaseProductsResp struct {
 	ReleaseProductsInfo []ReleaseProductResp `json:"release_products_info"`
 }
 
+type CommitReservationReq struct {
+	ReservationId string `json:"reservation_id"`
+}
+
+type CommitReservationResp struct {
+	ReservationId string            `json:"reservation_id"`
+	Status        ReservationStatus `json:"status"`
+}
+
+type GetReservationReq struct {
+	ReservationId string `json:"reservation_id"`
+}
+
+type GetReservationResp struct {
+	ReservationId string            `json:"reservation_id"`
+	Status        ReservationStatus `json:"status"`
+	Lines         []ReservationLine `json:"lines"`
+}
+
+type CancelReservationReq struct {
+	ReservationId string `json:"reservation_id"`
+}
+
+type CancelledProductResp struct {
+	Code        string `json:"code"`
+	WarehouseId int    `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+type CancelReservationResp struct {
+	ReservationId         string                 `json:"reservation_id"`
+	Status                ReservationStatus      `json:"status"`
+	CancelledProductsInfo []CancelledProductResp `json:"cancelled_products_info"`
+}
+
 type ShowProductsReq struct {
 	WarehouseId int `json:"warehouse_id"`
 }
+
+type CreateWarehouseReq struct {
+	Name         string    `json:"name"`
+	Availability bool      `json:"availability"`
+	Priority     int       `json:"priority"`
+	Region       string    `json:"region"`
+	Location     *GeoPoint `json:"location"`
+}
+
+type UpdateWarehouseReq struct {
+	WarehouseId int    `json:"warehouse_id"`
+	Name        string `json:"name"`
+}
+
+type SetWarehouseAvailabilityReq struct {
+	WarehouseId  int  `json:"warehouse_id"`
+	Availability bool `json:"availability"`
+}
+
+type SetWarehouseRoutingReq struct {
+	WarehouseId int       `json:"warehouse_id"`
+	Priority    int       `json:"priority"`
+	Region      string    `json:"region"`
+	Location    *GeoPoint `json:"location"`
+}
+
+type ListWarehousesReq struct{}
+
+type CreateProductReq struct {
+	Name string `json:"name"`
+	Size string `json:"size"`
+	Code string `json:"code"`
+}
+
+type UpdateProductReq struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Size string `json:"size"`
+	Code string `json:"code"`
+}
+
+type GetProductByCodeReq struct {
+	Code string `json:"code"`
+}
+
+type ListProductsReq struct {
+	Name   string `json:"name"`
+	Size   string `json:"size"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+type ListProductsResp struct {
+	Products []CatalogProduct `json:"products"`
+	Total    int              `json:"total"`
+}
+
+type ReceiveStockLineReq struct {
+	Code     string `json:"code"`
+	Quantity int    `json:"quantity"`
+}
+
+type ReceiveStockReq struct {
+	SupplierRef string                `json:"supplier_ref"`
+	WarehouseId int                   `json:"warehouse_id"`
+	Lines       []ReceiveStockLineReq `json:"lines"`
+}
+
+type ReceivedLineResp struct {
+	Code              string `json:"code"`
+	Quantity          int    `json:"quantity"`
+	WarehouseQuantity int    `json:"warehouse_quantity"`
+}
+
+type ReceiveStockResp struct {
+	ReceiptId   int                `json:"receipt_id"`
+	SupplierRef string             `json:"supplier_ref"`
+	WarehouseId int                `json:"warehouse_id"`
+	ReceivedAt  time.Time          `json:"received_at"`
+	Lines       []ReceivedLineResp `json:"lines"`
+}
+
+type TransferStockReq struct {
+	Code            string `json:"code"`
+	FromWarehouseId int    `json:"from_warehouse_id"`
+	ToWarehouseId   int    `json:"to_warehouse_id"`
+	Quantity        int    `json:"quantity"`
+	InTransit       bool   `json:"in_transit"`
+}
+
+type ReceiveTransferReq struct {
+	TransferId int `json:"transfer_id"`
+}
+
+type GetStockMovementsReq struct {
+	Code        string     `json:"code"`
+	WarehouseId int        `json:"warehouse_id"`
+	From        *time.Time `json:"from"`
+	To          *time.Time `json:"to"`
+	Limit       int        `json:"limit"`
+	Offset      int        `json:"offset"`
+}
+
+type GetStockMovementsResp struct {
+	Movements []StockMovement `json:"movements"`
+}
+
+type AdjustStockReq struct {
+	WarehouseId     int              `json:"warehouse_id"`
+	Code            string           `json:"code"`
+	CountedQuantity *int             `json:"counted_quantity"`
+	Delta           *int             `json:"delta"`
+	Reason          AdjustmentReason `json:"reason"`
+}
+
+type AdjustStockResp struct {
+	AdjustmentId int              `json:"adjustment_id"`
+	WarehouseId  int              `json:"warehouse_id"`
+	Code         string           `json:"code"`
+	Reason       AdjustmentReason `json:"reason"`
+	Delta        int              `json:"delta"`
+	Quantity     int              `json:"quantity"`
+	Reserved     int              `json:"reserved"`
+}
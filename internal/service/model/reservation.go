@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	ReservationReserved  ReservationStatus = "reserved"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationCommitted ReservationStatus = "committed"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// reservationTransitions lists the statuses a reservation is allowed to move to.
+// Released, committed and expired reservations are final.
+var reservationTransitions = map[ReservationStatus][]ReservationStatus{
+	ReservationReserved: {ReservationReleased, ReservationCommitted, ReservationExpired},
+}
+
+func (s ReservationStatus) CanTransitionTo(next ReservationStatus) bool {
+	for _, status := range reservationTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AggregateReservationStatus returns the status of a reservation built from the statuses
+// of its lines: a reservation stays reserved while any line still holds stock.
+func AggregateReservationStatus(statuses []ReservationStatus) ReservationStatus {
+	var aggregated ReservationStatus = ReservationReleased
+	for _, status := range statuses {
+		switch status {
+		case ReservationReserved:
+			return ReservationReserved
+		case ReservationCommitted:
+			aggregated = ReservationCommitted
+		case ReservationExpired:
+			if aggregated != ReservationCommitted {
+				aggregated = ReservationExpired
+			}
+		}
+	}
+
+	return aggregated
+}
+
+type ReservationLine struct {
+	Code          string            `json:"code"`
+	Name          string            `json:"name"`
+	Size          string            `json:"size"`
+	WarehouseId   int               `json:"warehouse_id"`
+	WarehouseName string            `json:"warehouse_name"`
+	Quantity      int               `json:"quantity"`
+	ReservedAt    time.Time         `json:"reserved_at"`
+	Status        ReservationStatus `json:"status"`
+}
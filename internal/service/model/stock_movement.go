@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type MovementReason string
+
+const (
+	MovementReserve     MovementReason = "reserve"
+	MovementRelease     MovementReason = "release"
+	MovementCancel      MovementReason = "cancel"
+	MovementExpire      MovementReason = "expire"
+	MovementReceive     MovementReason = "receive"
+	MovementTransferOut MovementReason = "transfer_out"
+	MovementTransferIn  MovementReason = "transfer_in"
+	MovementAdjust      MovementReason = "adjust"
+)
+
+// StockMovement is a single change of product quantity in a warehouse.
+// ReferenceId points to the reservation, receipt or transfer that caused it.
+type StockMovement struct {
+	ID          int64          `json:"id"`
+	WarehouseId int            `json:"warehouse_id"`
+	Code        string         `json:"code"`
+	Delta       int            `json:"delta"`
+	Reason      MovementReason `json:"reason"`
+	ReferenceId string         `json:"reference_id"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type TransferStatus string
+
+const (
+	// TransferShipped means stock has left the source warehouse but hasn't arrived yet,
+	// so it can't be reserved in either warehouse
+	TransferShipped  TransferStatus = "shipped"
+	TransferReceived TransferStatus = "received"
+)
+
+type Transfer struct {
+	ID              int            `json:"transfer_id"`
+	Code            string         `json:"code"`
+	FromWarehouseId int            `json:"from_warehouse_id"`
+	ToWarehouseId   int            `json:"to_warehouse_id"`
+	Quantity        int            `json:"quantity"`
+	Status          TransferStatus `json:"status"`
+	ShippedAt       time.Time      `json:"shipped_at"`
+	ReceivedAt      *time.Time     `json:"received_at,omitempty"`
+}
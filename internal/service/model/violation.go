@@ -0,0 +1,24 @@
+package model
+
+import "fmt"
+
+// Violation is a failed check of a request field, Field is a path like products[2].quantity
+type Violation struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+type Violations []Violation
+
+func (v *Violations) Add(field, reason string, args ...interface{}) {
+	*v = append(*v, Violation{Field: field, Reason: fmt.Sprintf(reason, args...)})
+}
+
+// Err returns ErrValidation with all violations in details, nil if there are none
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	return WithDetails(ErrValidation, map[string]interface{}{"violations": v})
+}
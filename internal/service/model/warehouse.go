@@ -0,0 +1,20 @@
+package model
+
+type Warehouse struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Availability bool      `json:"availability"`
+	Priority     int       `json:"priority"`
+	Region       string    `json:"region"`
+	Location     *GeoPoint `json:"location,omitempty"`
+}
+
+// GeoPoint is a position in degrees
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (p GeoPoint) IsValid() bool {
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+}
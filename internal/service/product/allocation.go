@@ -0,0 +1,189 @@
+package product
+
+import (
+	"math"
+	"sort"
+
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+// AllocationStrategy decides which warehouses a reservation line takes products from.
+// Allocate returns warehouses in the order they are drained; warehouses left out are not touched.
+type AllocationStrategy interface {
+	Allocate(stock []repoModel.ProductsOnActiveWarehouse, quantity int) ([]repoModel.ProductsOnActiveWarehouse, error)
+}
+
+// capacityLimiter is implemented by strategies unable to use all of the stock, a partial line is capped by the capacity
+type capacityLimiter interface {
+	Capacity(stock []repoModel.ProductsOnActiveWarehouse) int
+}
+
+// newAllocationStrategy builds the strategy requested by name with its parameters taken from the request
+func newAllocationStrategy(name model.AllocationStrategyName, args *model.ReserveProductsReq) (AllocationStrategy, error) {
+	switch name {
+	case model.StrategyLargestStockFirst:
+		return largestStockFirst{}, nil
+	case model.StrategyFewestWarehouses:
+		return fewestWarehouses{}, nil
+	case model.StrategyWarehousePriority:
+		return warehousePriority{priority: args.WarehousePriority}, nil
+	case model.StrategySingleWarehouse:
+		return singleWarehouse{}, nil
+	case model.StrategyNearest:
+		if args.Destination == nil && args.DestinationRegion == "" {
+			return nil, model.ErrInvalidDestination
+		}
+		if args.Destination != nil && !args.Destination.IsValid() {
+			return nil, model.ErrInvalidLocation
+		}
+		return nearest{region: args.DestinationRegion, destination: args.Destination}, nil
+	default:
+		return nil, model.ErrInvalidStrategy
+	}
+}
+
+// byQuantityDesc returns a copy of stock sorted from the largest quantity, ties broken by warehouse id
+func byQuantityDesc(stock []repoModel.ProductsOnActiveWarehouse) []repoModel.ProductsOnActiveWarehouse {
+	sorted := make([]repoModel.ProductsOnActiveWarehouse, len(stock))
+	copy(sorted, stock)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Quantity != sorted[j].Quantity {
+			return sorted[i].Quantity > sorted[j].Quantity
+		}
+		return sorted[i].WarehouseId < sorted[j].WarehouseId
+	})
+
+	return sorted
+}
+
+// largestStockFirst drains warehouses starting from the one with the most products
+type largestStockFirst struct{}
+
+func (largestStockFirst) Allocate(stock []repoModel.ProductsOnActiveWarehouse, _ int) ([]repoModel.ProductsOnActiveWarehouse, error) {
+	return byQuantityDesc(stock), nil
+}
+
+// fewestWarehouses touches as few warehouses as possible. The last warehouse is the smallest one
+// that still covers the rest of the line, so large stocks stay intact where it doesn't cost an extra warehouse.
+type fewestWarehouses struct{}
+
+func (fewestWarehouses) Allocate(stock []repoModel.ProductsOnActiveWarehouse, quantity int) ([]repoModel.ProductsOnActiveWarehouse, error) {
+	sorted := byQuantityDesc(stock)
+
+	var taken int
+	for i := range sorted {
+		rest := quantity - taken
+		if sorted[i].Quantity < rest {
+			taken += sorted[i].Quantity
+			continue
+		}
+
+		last := i
+		for j := i + 1; j < len(sorted) && sorted[j].Quantity >= rest; j++ {
+			last = j
+		}
+
+		plan := append(sorted[:i:i], sorted[last])
+		return plan, nil
+	}
+
+	return sorted, nil
+}
+
+// warehousePriority drains warehouses in the order given by the client, warehouses missing
+// from the list follow by their configured priority, highest first, and then by largest stock
+type warehousePriority struct {
+	priority []int
+}
+
+func (p warehousePriority) Allocate(stock []repoModel.ProductsOnActiveWarehouse, _ int) ([]repoModel.ProductsOnActiveWarehouse, error) {
+	rank := make(map[int]int, len(p.priority))
+	for i, warehouseId := range p.priority {
+		if _, ok := rank[warehouseId]; !ok {
+			rank[warehouseId] = i
+		}
+	}
+
+	sorted := byQuantityDesc(stock)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, okI := rank[sorted[i].WarehouseId]
+		rj, okJ := rank[sorted[j].WarehouseId]
+		switch {
+		case okI && okJ:
+			return ri < rj
+		case okI != okJ:
+			return okI
+		default:
+			return sorted[i].Priority > sorted[j].Priority
+		}
+	})
+
+	return sorted, nil
+}
+
+// nearest drains warehouses closest to the destination first: warehouses in the destination region
+// go before the others, then by distance to the destination point. Warehouses without a location
+// are treated as the farthest ones, ties are broken by largest stock.
+type nearest struct {
+	region      string
+	destination *model.GeoPoint
+}
+
+func (n nearest) Allocate(stock []repoModel.ProductsOnActiveWarehouse, _ int) ([]repoModel.ProductsOnActiveWarehouse, error) {
+	sorted := byQuantityDesc(stock)
+
+	distances := make(map[int]float64, len(sorted))
+	for _, wh := range sorted {
+		distances[wh.WarehouseId] = math.Inf(1)
+		if n.destination != nil && wh.Location != nil {
+			distances[wh.WarehouseId] = distanceKm(*n.destination, *wh.Location)
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if n.region != "" {
+			inI, inJ := sorted[i].Region == n.region, sorted[j].Region == n.region
+			if inI != inJ {
+				return inI
+			}
+		}
+		return distances[sorted[i].WarehouseId] < distances[sorted[j].WarehouseId]
+	})
+
+	return sorted, nil
+}
+
+const earthRadiusKm = 6371
+
+// distanceKm returns the great-circle distance between two points
+func distanceKm(a, b model.GeoPoint) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// singleWarehouse reserves the whole line from one warehouse, the one with the most products
+type singleWarehouse struct{}
+
+func (singleWarehouse) Allocate(stock []repoModel.ProductsOnActiveWarehouse, quantity int) ([]repoModel.ProductsOnActiveWarehouse, error) {
+	sorted := byQuantityDesc(stock)
+	if len(sorted) == 0 || sorted[0].Quantity < quantity {
+		return nil, model.ErrNoSingleWarehouse
+	}
+
+	return sorted[:1], nil
+}
+
+// Capacity is the quantity of the warehouse with the most products
+func (singleWarehouse) Capacity(stock []repoModel.ProductsOnActiveWarehouse) int {
+	var capacity int
+	for _, s := range stock {
+		capacity = max(capacity, s.Quantity)
+	}
+
+	return capacity
+}
@@ -0,0 +1,122 @@
+package product
+
+import (
+	"testing"
+
+	repoModel "github.com/pintoter/warehouse-api/internal/repository/model"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocationStrategies(t *testing.T) {
+	stock := []repoModel.ProductsOnActiveWarehouse{
+		{WarehouseId: 1, ProductId: 7, Quantity: 2, Region: "moscow", Location: &model.GeoPoint{Latitude: 55.75, Longitude: 37.62}},
+		{WarehouseId: 2, ProductId: 7, Quantity: 10, Region: "spb", Location: &model.GeoPoint{Latitude: 59.94, Longitude: 30.31}},
+		{WarehouseId: 3, ProductId: 7, Quantity: 5, Priority: 1, Region: "kazan"},
+		{WarehouseId: 4, ProductId: 7, Quantity: 6, Priority: 2, Region: "moscow", Location: &model.GeoPoint{Latitude: 55.43, Longitude: 37.55}},
+	}
+
+	type args struct {
+		strategy model.AllocationStrategyName
+		req      model.ReserveProductsReq
+		quantity int
+	}
+
+	tests := []struct {
+		name           string
+		args           args
+		wantWarehouses []int
+		wantErr        error
+	}{
+		{
+			name:           "Largest stock first",
+			args:           args{strategy: model.StrategyLargestStockFirst, quantity: 12},
+			wantWarehouses: []int{2, 4, 3, 1},
+		},
+		{
+			name:           "Fewest warehouses single best fit",
+			args:           args{strategy: model.StrategyFewestWarehouses, quantity: 5},
+			wantWarehouses: []int{3},
+		},
+		{
+			name:           "Fewest warehouses best fit for the rest",
+			args:           args{strategy: model.StrategyFewestWarehouses, quantity: 12},
+			wantWarehouses: []int{2, 1},
+		},
+		{
+			name:           "Fewest warehouses not enough stock",
+			args:           args{strategy: model.StrategyFewestWarehouses, quantity: 30},
+			wantWarehouses: []int{2, 4, 3, 1},
+		},
+		{
+			name:           "Warehouse priority",
+			args:           args{strategy: model.StrategyWarehousePriority, req: model.ReserveProductsReq{WarehousePriority: []int{3, 1}}, quantity: 4},
+			wantWarehouses: []int{3, 1, 4, 2},
+		},
+		{
+			name:           "Warehouse priority from warehouses",
+			args:           args{strategy: model.StrategyWarehousePriority, quantity: 4},
+			wantWarehouses: []int{4, 3, 2, 1},
+		},
+		{
+			name:           "Nearest by coordinates",
+			args:           args{strategy: model.StrategyNearest, req: model.ReserveProductsReq{Destination: &model.GeoPoint{Latitude: 55.45, Longitude: 37.56}}, quantity: 4},
+			wantWarehouses: []int{4, 1, 2, 3},
+		},
+		{
+			name:           "Nearest by region",
+			args:           args{strategy: model.StrategyNearest, req: model.ReserveProductsReq{DestinationRegion: "moscow"}, quantity: 4},
+			wantWarehouses: []int{4, 1, 2, 3},
+		},
+		{
+			name:           "Nearest by region and coordinates",
+			args:           args{strategy: model.StrategyNearest, req: model.ReserveProductsReq{DestinationRegion: "kazan", Destination: &model.GeoPoint{Latitude: 59.9, Longitude: 30.3}}, quantity: 4},
+			wantWarehouses: []int{3, 2, 1, 4},
+		},
+		{
+			name:    "Nearest without destination",
+			args:    args{strategy: model.StrategyNearest, quantity: 4},
+			wantErr: model.ErrInvalidDestination,
+		},
+		{
+			name:    "Nearest with invalid destination",
+			args:    args{strategy: model.StrategyNearest, req: model.ReserveProductsReq{Destination: &model.GeoPoint{Latitude: 91}}, quantity: 4},
+			wantErr: model.ErrInvalidLocation,
+		},
+		{
+			name:           "Single warehouse",
+			args:           args{strategy: model.StrategySingleWarehouse, quantity: 10},
+			wantWarehouses: []int{2},
+		},
+		{
+			name:    "Single warehouse not enough stock",
+			args:    args{strategy: model.StrategySingleWarehouse, quantity: 11},
+			wantErr: model.ErrNoSingleWarehouse,
+		},
+		{
+			name:    "Unknown strategy",
+			args:    args{strategy: "random", quantity: 1},
+			wantErr: model.ErrInvalidStrategy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := newAllocationStrategy(tt.args.strategy, &tt.args.req)
+			if err == nil {
+				var plan []repoModel.ProductsOnActiveWarehouse
+				plan, err = strategy.Allocate(stock, tt.args.quantity)
+
+				warehouses := make([]int, 0, len(plan))
+				for _, p := range plan {
+					warehouses = append(warehouses, p.WarehouseId)
+				}
+				if tt.wantErr == nil {
+					assert.Equal(t, tt.wantWarehouses, warehouses)
+				}
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
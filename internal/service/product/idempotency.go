@@ -0,0 +1,111 @@
+package product
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/pintoter/warehouse-api/pkg/logger"
+)
+
+const (
+	reserveProductsMethod = "ProductService.ReserveProducts"
+	releaseProductsMethod = "ProductService.ReleaseProducts"
+)
+
+// withIdempotency runs call at most once per idempotency key. The key is claimed, call is run and
+// its reply is stored in one transaction, which the transactions of call join, so a key is never
+// left claimed without a reply: a failed call releases it and a concurrent replay waits for the first
+// call to get the stored reply. A replay with a different payload is rejected.
+func (s *Service) withIdempotency(ctx context.Context, method, key string, args, reply interface{}, call func(ctx context.Context) error) error {
+	if key == "" {
+		return call(ctx)
+	}
+
+	if len(key) > model.MaxIdempotencyKeyLength {
+		return model.ErrInvalidInput
+	}
+
+	requestHash, err := hashRequest(args)
+	if err != nil {
+		return model.ErrInvalidInput
+	}
+
+	var created bool
+	err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.repo.CreateIdempotencyKey(ctx, method, key, requestHash)
+		if err != nil {
+			logger.DebugKV(ctx, "Idempotency", "err", err)
+			return repoErr(err, model.ErrInternalServer)
+		}
+
+		if !created {
+			return nil
+		}
+
+		if err = call(ctx); err != nil {
+			return err
+		}
+
+		response, err := json.Marshal(reply)
+		if err != nil {
+			return model.ErrInternalServer
+		}
+
+		if err = s.repo.UpdateIdempotencyResponse(ctx, method, key, response); err != nil {
+			logger.DebugKV(ctx, "Idempotency", "err", err)
+			return repoErr(err, model.ErrInternalServer)
+		}
+
+		return nil
+	})
+	if err != nil && (ctx.Err() != nil || dbutil.IsRetryable(err)) {
+		err = model.ErrInternalServer
+	}
+
+	if err != nil || created {
+		return err
+	}
+
+	return s.replay(ctx, method, key, requestHash, reply)
+}
+
+func (s *Service) replay(ctx context.Context, method, key, requestHash string, reply interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	stored, err := s.repo.GetIdempotencyKey(ctx, method, key)
+	if err != nil {
+		logger.DebugKV(ctx, "Idempotency", "err", err)
+		return model.ErrInternalServer
+	}
+
+	if stored.RequestHash != requestHash {
+		return model.ErrIdempotencyKeyReused
+	}
+
+	if stored.Response == nil {
+		return model.ErrIdempotencyKeyInProgress
+	}
+
+	if err = json.Unmarshal(stored.Response, reply); err != nil {
+		return model.ErrInternalServer
+	}
+
+	return nil
+}
+
+func hashRequest(args interface{}) (string, error) {
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
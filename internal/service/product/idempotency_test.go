@@ -0,0 +1,218 @@
+package product
+
+import (
+	"errors"
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReserveProductsIdempotency(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}), txConfig{})
+
+	type mockBehavior func(requestHash string)
+
+	expectedClaimQuery := "INSERT INTO idempotency_key (method,key,request_hash) VALUES ($1,$2,$3) ON CONFLICT (method, key) DO NOTHING"
+	expectedGetQuery := "SELECT method, key, request_hash, response FROM idempotency_key WHERE key = $1 AND method = $2"
+	expectedUpdateResponseQuery := "UPDATE idempotency_key SET response = $1 WHERE key = $2 AND method = $3"
+	expectedTotalQuery := "WITH total_products AS"
+	expectedWarehousesQuery := "SELECT wp.warehouse_id, wp.product_id, wp.quantity, w.priority, w.region, w.latitude, w.longitude FROM warehouse_product wp"
+	expectedUpdateQuery := "UPDATE warehouse_product SET quantity = $1 WHERE product_id = $2 AND warehouse_id = $3"
+	expectedInsertQuery := "INSERT INTO reservation (reservation_id,warehouse_id,product_id,quantity) VALUES ($1,$2,$3,$4) RETURNING id"
+	expectedMovementQuery := "INSERT INTO stock_movement (warehouse_id,product_id,delta,reason,reference_id) VALUES ($1,$2,$3,$4,$5)"
+
+	key := "order-1"
+	args := &model.ReserveProductsReq{
+		Products:       []model.ReserveProductReq{{Code: "12345", Quantity: 0}},
+		IdempotencyKey: key,
+	}
+	storedReply := model.ReserveProductsResp{
+		ReservationId:           "51ef143e-4c4d-4b8b-a4dc-700ede2832e2",
+		ReservationProductsInfo: []model.ReserveProductResp{{Code: "12345", Status: reserved}},
+	}
+
+	requestHash, err := hashRequest(args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reserveArgs := &model.ReserveProductsReq{
+		Products:       []model.ReserveProductReq{{Code: "12345", Quantity: 1}},
+		IdempotencyKey: key,
+	}
+	reserveHash, err := hashRequest(reserveArgs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// expectReservation expects the line of reserveArgs to be reserved in a savepoint of the claiming transaction
+	expectReservation := func() {
+		mock.ExpectExec("SAVEPOINT nested").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+			WithArgs("12345").
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(3))
+		mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+			WithArgs("12345", true).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+				AddRow(1, 1, 3, 0, "", nil, nil))
+		mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+			WithArgs(2, 1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+			WithArgs(sqlmock.AnyArg(), 1, 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+			WithArgs(1, 1, -1, model.MovementReserve, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("RELEASE SAVEPOINT nested").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.ReserveProductsReq
+		wantReply    model.ReserveProductsResp
+		wantStatus   string
+		wantErr      error
+	}{
+		{
+			name: "Replay",
+			mockBehavior: func(requestHash string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedClaimQuery)).
+					WithArgs(reserveProductsMethod, key, requestHash).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedGetQuery)).
+					WithArgs(key, reserveProductsMethod).
+					WillReturnRows(sqlmock.NewRows([]string{"method", "key", "request_hash", "response"}).
+						AddRow(reserveProductsMethod, key, requestHash,
+							[]byte(`{"reservation_id":"51ef143e-4c4d-4b8b-a4dc-700ede2832e2","reservation_products_info":[{"code":"12345","status":"reserved"}]}`)))
+			},
+			args:      args,
+			wantReply: storedReply,
+		},
+		{
+			name: "Payload differs",
+			mockBehavior: func(requestHash string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedClaimQuery)).
+					WithArgs(reserveProductsMethod, key, requestHash).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedGetQuery)).
+					WithArgs(key, reserveProductsMethod).
+					WillReturnRows(sqlmock.NewRows([]string{"method", "key", "request_hash", "response"}).
+						AddRow(reserveProductsMethod, key, "another hash", []byte(`{}`)))
+			},
+			args:    args,
+			wantErr: model.ErrIdempotencyKeyReused,
+		},
+		{
+			name: "In progress",
+			mockBehavior: func(requestHash string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedClaimQuery)).
+					WithArgs(reserveProductsMethod, key, requestHash).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedGetQuery)).
+					WithArgs(key, reserveProductsMethod).
+					WillReturnRows(sqlmock.NewRows([]string{"method", "key", "request_hash", "response"}).
+						AddRow(reserveProductsMethod, key, requestHash, nil))
+			},
+			args:    args,
+			wantErr: model.ErrIdempotencyKeyInProgress,
+		},
+		{
+			name: "Key released on failed call",
+			mockBehavior: func(_ string) {
+				emptyArgs := &model.ReserveProductsReq{IdempotencyKey: key}
+				requestHash, err := hashRequest(emptyArgs)
+				if err != nil {
+					log.Fatal(err)
+				}
+
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedClaimQuery)).
+					WithArgs(reserveProductsMethod, key, requestHash).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			args:    &model.ReserveProductsReq{IdempotencyKey: key},
+			wantErr: model.ErrInvalidInput,
+		},
+		{
+			name: "Reply stored with the reservation",
+			mockBehavior: func(_ string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedClaimQuery)).
+					WithArgs(reserveProductsMethod, key, reserveHash).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectReservation()
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateResponseQuery)).
+					WithArgs(sqlmock.AnyArg(), key, reserveProductsMethod).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			args:       reserveArgs,
+			wantStatus: reserved,
+		},
+		{
+			name: "Reservation rolled back on failed store",
+			mockBehavior: func(_ string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(expectedClaimQuery)).
+					WithArgs(reserveProductsMethod, key, reserveHash).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectReservation()
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateResponseQuery)).
+					WithArgs(sqlmock.AnyArg(), key, reserveProductsMethod).
+					WillReturnError(errors.New("connection reset"))
+				mock.ExpectRollback()
+			},
+			args:    reserveArgs,
+			wantErr: model.ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(requestHash)
+
+			var reply model.ReserveProductsResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.ReserveProducts(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else if tt.wantStatus != "" {
+				assert.NoError(t, err)
+				if assert.Len(t, reply.ReservationProductsInfo, 1) {
+					assert.Equal(t, tt.wantStatus, reply.ReservationProductsInfo[0].Status)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
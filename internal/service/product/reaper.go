@@ -0,0 +1,133 @@
+package product
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	"github.com/pintoter/warehouse-api/internal/repository"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/pintoter/warehouse-api/pkg/logger"
+)
+
+const defaultReaperBatchSize = 100
+
+type ReaperConfig interface {
+	GetInterval() time.Duration
+	GetBatchSize() int
+}
+
+// Reaper periodically returns the stock held by expired reservations back to warehouses
+type Reaper struct {
+	service   *Service
+	interval  time.Duration
+	batchSize int
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+func NewReaper(repo repository.Repository, txManager dbutil.TxManager, cfg ReaperConfig) *Reaper {
+	batchSize := cfg.GetBatchSize()
+	if batchSize <= 0 {
+		batchSize = defaultReaperBatchSize
+	}
+
+	return &Reaper{
+		service: &Service{
+			repo:      repo,
+			txManager: txManager,
+		},
+		interval:  cfg.GetInterval(),
+		batchSize: batchSize,
+		quit:      make(chan struct{}),
+	}
+}
+
+func (r *Reaper) Run() {
+	if r.interval <= 0 {
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.quit:
+				return
+			case <-ticker.C:
+				r.reap()
+			}
+		}
+	}()
+}
+
+func (r *Reaper) Shutdown() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *Reaper) reap() {
+	ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+	defer cancel()
+
+	for {
+		released, err := r.service.releaseExpired(ctx, r.batchSize)
+		if err != nil {
+			logger.ErrorKV(ctx, "Failed release expired reservations", "err", err)
+			return
+		}
+
+		if released > 0 {
+			logger.InfoKV(ctx, "Expired reservations released", "count", released)
+		}
+
+		if released < r.batchSize {
+			return
+		}
+	}
+}
+
+// releaseExpired returns the stock of up to limit expired reservation lines to warehouses
+// and marks those lines expired. It returns the number of released lines.
+func (s *Service) releaseExpired(ctx context.Context, limit int) (int, error) {
+	var released int
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		expiredReservations, err := s.repo.GetExpiredReservations(ctx, limit)
+		if err != nil {
+			return err
+		}
+
+		if len(expiredReservations) == 0 {
+			return nil
+		}
+
+		var (
+			quantity int
+			ids      = make([]int, 0, len(expiredReservations))
+		)
+		for _, expiredReservation := range expiredReservations {
+			quantity += expiredReservation.Quantity
+			ids = append(ids, expiredReservation.ID)
+		}
+
+		if err = s.startRelease(ctx, expiredReservations, quantity, model.MovementExpire); err != nil {
+			return err
+		}
+
+		if err = s.repo.MarkReservationsExpired(ctx, ids); err != nil {
+			return err
+		}
+
+		released = len(expiredReservations)
+		return nil
+	})
+
+	return released, err
+}
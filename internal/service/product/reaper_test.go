@@ -0,0 +1,134 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := &Service{
+		repo:      productRepository.NewRepository(sqlxDB),
+		txManager: transaction.NewTransactionManager(sqlxDB, txConfig{}),
+	}
+
+	type mockBehavior func()
+
+	expectedExpiredQuery := "SELECT id, reservation_id, warehouse_id, product_id, quantity FROM reservation WHERE (expires_at <= CURRENT_TIMESTAMP AND status = $1)"
+	expectedReservationUpdate := "UPDATE reservation SET quantity = $1 WHERE id = $2"
+	expectedWarehouseUpdate := "UPDATE warehouse_product SET quantity = quantity + $1 WHERE product_id = $2 AND warehouse_id = $3"
+	expectedStatusUpdate := "UPDATE reservation SET status = $1 WHERE id = $2"
+	expectedMarkExpired := "UPDATE reservation SET expired_at = CURRENT_TIMESTAMP, status = $1 WHERE id IN ($2,$3)"
+	expectedMovementQuery := "INSERT INTO stock_movement (warehouse_id,product_id,delta,reason,reference_id) VALUES ($1,$2,$3,$4,$5)"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantReleased int
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExpiredQuery)).
+					WithArgs(model.ReservationReserved).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "reservation_id", "warehouse_id", "product_id", "quantity"}).
+						AddRow(4, "422ab5fa-fbf1-461a-99dc-2c6a49c323f1", 1, 2, 3).
+						AddRow(7, "965ac486-0451-4e87-be55-2f985cdbf292", 2, 5, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedReservationUpdate)).
+					WithArgs(0, 4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedStatusUpdate)).
+					WithArgs(model.ReservationReleased, 4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedWarehouseUpdate)).
+					WithArgs(3, 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(1, 2, 3, model.MovementExpire, "422ab5fa-fbf1-461a-99dc-2c6a49c323f1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedReservationUpdate)).
+					WithArgs(0, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedStatusUpdate)).
+					WithArgs(model.ReservationReleased, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedWarehouseUpdate)).
+					WithArgs(1, 5, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(2, 5, 1, model.MovementExpire, "965ac486-0451-4e87-be55-2f985cdbf292").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMarkExpired)).
+					WithArgs(model.ReservationExpired, 4, 7).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			wantReleased: 2,
+		},
+		{
+			name: "Nothing expired",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExpiredQuery)).
+					WithArgs(model.ReservationReserved).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "reservation_id", "warehouse_id", "product_id", "quantity"}))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Rollback on failed warehouse update",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExpiredQuery)).
+					WithArgs(model.ReservationReserved).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "reservation_id", "warehouse_id", "product_id", "quantity"}).
+						AddRow(4, "422ab5fa-fbf1-461a-99dc-2c6a49c323f1", 1, 2, 3))
+				mock.ExpectExec(regexp.QuoteMeta(expectedReservationUpdate)).
+					WithArgs(0, 4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedStatusUpdate)).
+					WithArgs(model.ReservationReleased, 4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedWarehouseUpdate)).
+					WithArgs(3, 2, 1).
+					WillReturnError(errors.New("any error"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			gotReleased, err := s.releaseExpired(context.Background(), 100)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReleased, gotReleased)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
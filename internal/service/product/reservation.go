@@ -0,0 +1,163 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/pintoter/warehouse-api/pkg/logger"
+)
+
+// CommitReservation turns the stock held by a reservation into an outbound shipment:
+// the reserved quantity is never returned to warehouses and the reservation can't be released anymore
+func (s *Service) CommitReservation(r *http.Request, args *model.CommitReservationReq, reply *model.CommitReservationResp) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		err := s.checkReservationStatus(ctx, args.ReservationId, model.ReservationCommitted)
+		if err != nil {
+			return err
+		}
+
+		err = s.repo.UpdateReservationsStatus(ctx, args.ReservationId, model.ReservationReserved, model.ReservationCommitted)
+		if err != nil {
+			return repoErr(err, model.ErrInternalServer)
+		}
+
+		return nil
+	})
+	if err != nil && (ctx.Err() != nil || dbutil.IsRetryable(err)) {
+		err = model.ErrInternalServer
+	}
+
+	if err != nil {
+		*reply = model.CommitReservationResp{}
+		return err
+	}
+
+	*reply = model.CommitReservationResp{
+		ReservationId: args.ReservationId,
+		Status:        model.ReservationCommitted,
+	}
+	return nil
+}
+
+// CancelReservation returns all stock held by the reservation to warehouses in one transaction
+func (s *Service) CancelReservation(r *http.Request, args *model.CancelReservationReq, reply *model.CancelReservationResp) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(args.ReservationId); err != nil {
+		*reply = model.CancelReservationResp{}
+		return model.ErrInvalidInput
+	}
+
+	var productsInfo []model.CancelledProductResp
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		productsInfo = nil
+
+		err := s.checkReservationStatus(ctx, args.ReservationId, model.ReservationReleased)
+		if err != nil {
+			return err
+		}
+
+		productsInReservation, err := s.repo.GetProductsByReservationId(ctx, args.ReservationId)
+		if err != nil {
+			return repoErr(err, model.ErrInternalServer)
+		}
+
+		var quantity int
+		for _, productInReservation := range productsInReservation {
+			quantity += productInReservation.Quantity
+			productsInfo = append(productsInfo, model.CancelledProductResp{
+				Code:        productInReservation.Code,
+				WarehouseId: productInReservation.WarehouseId,
+				Quantity:    productInReservation.Quantity,
+			})
+		}
+
+		return s.startRelease(ctx, productsInReservation, quantity, model.MovementCancel)
+	})
+	if err != nil && (ctx.Err() != nil || dbutil.IsRetryable(err)) {
+		err = model.ErrInternalServer
+	}
+
+	if err != nil {
+		*reply = model.CancelReservationResp{}
+		return err
+	}
+
+	*reply = model.CancelReservationResp{
+		ReservationId:         args.ReservationId,
+		Status:                model.ReservationReleased,
+		CancelledProductsInfo: productsInfo,
+	}
+	return nil
+}
+
+// GetReservation returns every line held by the reservation
+func (s *Service) GetReservation(r *http.Request, args *model.GetReservationReq, reply *model.GetReservationResp) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(args.ReservationId); err != nil {
+		*reply = model.GetReservationResp{}
+		return model.ErrInvalidInput
+	}
+
+	var lines []model.ReservationLine
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		lines, err = s.repo.GetReservationById(ctx, args.ReservationId)
+		return err
+	}, dbutil.WithIsolation(sql.LevelReadCommitted), dbutil.WithReadOnly())
+	if err != nil {
+		logger.DebugKV(ctx, "GetReservation", "err", err)
+		*reply = model.GetReservationResp{}
+		return model.ErrInternalServer
+	}
+
+	if len(lines) == 0 {
+		*reply = model.GetReservationResp{}
+		return model.ErrReservationNotFound
+	}
+
+	statuses := make([]model.ReservationStatus, 0, len(lines))
+	for _, line := range lines {
+		statuses = append(statuses, line.Status)
+	}
+
+	*reply = model.GetReservationResp{
+		ReservationId: args.ReservationId,
+		Status:        model.AggregateReservationStatus(statuses),
+		Lines:         lines,
+	}
+	return nil
+}
+
+// checkReservationStatus locks the reservation and checks that it can be moved to the next status
+func (s *Service) checkReservationStatus(ctx context.Context, reservationId string, next model.ReservationStatus) error {
+	statuses, err := s.repo.GetReservationStatuses(ctx, reservationId)
+	if err != nil {
+		return repoErr(err, model.ErrInvalidInput)
+	}
+
+	if len(statuses) == 0 {
+		return model.ErrReservationNotFound
+	}
+
+	status := model.AggregateReservationStatus(statuses)
+	switch {
+	case status.CanTransitionTo(next):
+		return nil
+	case status == model.ReservationCommitted:
+		return model.ErrReservationCommitted
+	default:
+		return model.ErrInvalidReservationStatus
+	}
+}
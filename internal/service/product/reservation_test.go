@@ -0,0 +1,344 @@
+package product
+
+import (
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitReservation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}), txConfig{})
+
+	type mockBehavior func(reservationId string)
+
+	expectedStatusesQuery := "SELECT status FROM reservation WHERE reservation_id = $1 FOR UPDATE"
+	expectedCommitQuery := "UPDATE reservation SET status = $1 WHERE reservation_id = $2 AND status = $3"
+
+	reservationId := "422ab5fa-fbf1-461a-99dc-2c6a49c323f1"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantReply    model.CommitReservationResp
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(reservationId string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedStatusesQuery)).
+					WithArgs(reservationId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("reserved").AddRow("released"))
+				mock.ExpectExec(regexp.QuoteMeta(expectedCommitQuery)).
+					WithArgs(model.ReservationCommitted, reservationId, model.ReservationReserved).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantReply: model.CommitReservationResp{ReservationId: reservationId, Status: model.ReservationCommitted},
+		},
+		{
+			name: "Already committed",
+			mockBehavior: func(reservationId string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedStatusesQuery)).
+					WithArgs(reservationId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("committed"))
+				mock.ExpectRollback()
+			},
+			wantErr: model.ErrReservationCommitted,
+		},
+		{
+			name: "Expired",
+			mockBehavior: func(reservationId string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedStatusesQuery)).
+					WithArgs(reservationId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("expired").AddRow("released"))
+				mock.ExpectRollback()
+			},
+			wantErr: model.ErrInvalidReservationStatus,
+		},
+		{
+			name: "Not found",
+			mockBehavior: func(reservationId string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedStatusesQuery)).
+					WithArgs(reservationId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}))
+				mock.ExpectRollback()
+			},
+			wantErr: model.ErrReservationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(reservationId)
+
+			var reply model.CommitReservationResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.CommitReservation(req, &model.CommitReservationReq{ReservationId: reservationId}, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReleaseProductsOfCommittedReservation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}), txConfig{})
+
+	reservationId := "422ab5fa-fbf1-461a-99dc-2c6a49c323f1"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM reservation WHERE reservation_id = $1 FOR UPDATE")).
+		WithArgs(reservationId).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("committed"))
+	mock.ExpectRollback()
+
+	var reply model.ReleaseProductsResp
+	req := httptest.NewRequest("POST", "/rpc", nil)
+	err = s.ReleaseProducts(req, &model.ReleaseProductsReq{
+		Products: []model.ReleaseProductReq{{ReservationId: reservationId, Code: "12345", Quantity: 1}},
+	}, &reply)
+
+	assert.NoError(t, err)
+	if assert.Len(t, reply.ReleaseProductsInfo, 1) {
+		line := reply.ReleaseProductsInfo[0]
+		assert.Equal(t, reservationId, line.ReservationId)
+		assert.Equal(t, "12345", line.Code)
+		assert.Equal(t, rejected+model.ErrReservationCommitted.Error(), line.Status)
+		if assert.NotNil(t, line.Error) {
+			assert.Equal(t, model.CodeConflict, line.Error.Code)
+			assert.Equal(t, model.ErrReservationCommitted.Error(), line.Error.Message)
+		}
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetReservation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}), txConfig{})
+
+	type mockBehavior func(reservationId string)
+
+	expectedQuery := "SELECT p.code, p.name, p.size, w.id, w.name, r.quantity, r.reserved_at, r.status FROM reservation r"
+
+	reservedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"code", "name", "size", "id", "name", "quantity", "reserved_at", "status"}
+
+	tests := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		reservationId string
+		wantReply     model.GetReservationResp
+		wantErr       error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(reservationId string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(reservationId).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("12345", "Lacoste T-Shirt", "XS", 1, "Domodedovo", 0, reservedAt, "released").
+						AddRow("12346", "Lacoste T-Shirt", "S", 1, "Domodedovo", 4, reservedAt, "reserved"))
+				mock.ExpectCommit()
+			},
+			reservationId: "965ac486-0451-4e87-be55-2f985cdbf292",
+			wantReply: model.GetReservationResp{
+				ReservationId: "965ac486-0451-4e87-be55-2f985cdbf292",
+				Status:        model.ReservationReserved,
+				Lines: []model.ReservationLine{
+					{
+						Code:          "12345",
+						Name:          "Lacoste T-Shirt",
+						Size:          "XS",
+						WarehouseId:   1,
+						WarehouseName: "Domodedovo",
+						Quantity:      0,
+						ReservedAt:    reservedAt,
+						Status:        model.ReservationReleased,
+					},
+					{
+						Code:          "12346",
+						Name:          "Lacoste T-Shirt",
+						Size:          "S",
+						WarehouseId:   1,
+						WarehouseName: "Domodedovo",
+						Quantity:      4,
+						ReservedAt:    reservedAt,
+						Status:        model.ReservationReserved,
+					},
+				},
+			},
+		},
+		{
+			name: "Not found",
+			mockBehavior: func(reservationId string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(reservationId).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectCommit()
+			},
+			reservationId: "00000000-0000-0000-0000-000000000000",
+			wantErr:       model.ErrReservationNotFound,
+		},
+		{
+			name:          "Invalid id",
+			mockBehavior:  func(string) {},
+			reservationId: "not-a-uuid",
+			wantErr:       model.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.reservationId)
+
+			var reply model.GetReservationResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.GetReservation(req, &model.GetReservationReq{ReservationId: tt.reservationId}, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCancelReservation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}), txConfig{})
+
+	type mockBehavior func(reservationId string)
+
+	expectedStatusesQuery := "SELECT status FROM reservation WHERE reservation_id = $1 FOR UPDATE"
+	expectedProductsQuery := "SELECT r.id, r.reservation_id, r.warehouse_id, r.product_id, p.code, r.quantity FROM reservation r"
+	expectedReservationUpdate := "UPDATE reservation SET quantity = $1 WHERE id = $2"
+	expectedStatusUpdate := "UPDATE reservation SET status = $1 WHERE id = $2"
+	expectedWarehouseUpdate := "UPDATE warehouse_product SET quantity = quantity + $1 WHERE product_id = $2 AND warehouse_id = $3"
+	expectedMovementQuery := "INSERT INTO stock_movement (warehouse_id,product_id,delta,reason,reference_id) VALUES ($1,$2,$3,$4,$5)"
+
+	reservationId := "422ab5fa-fbf1-461a-99dc-2c6a49c323f1"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantReply    model.CancelReservationResp
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(reservationId string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedStatusesQuery)).
+					WithArgs(reservationId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("reserved").AddRow("reserved"))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedProductsQuery)).
+					WithArgs(reservationId, model.ReservationReserved).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "reservation_id", "warehouse_id", "product_id", "code", "quantity"}).
+						AddRow(1, reservationId, 1, 1, "12345", 3).
+						AddRow(2, reservationId, 2, 1, "12345", 2))
+				for _, row := range [][]int{{1, 1, 3}, {2, 2, 2}} {
+					mock.ExpectExec(regexp.QuoteMeta(expectedReservationUpdate)).
+						WithArgs(0, row[0]).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectExec(regexp.QuoteMeta(expectedStatusUpdate)).
+						WithArgs(model.ReservationReleased, row[0]).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectExec(regexp.QuoteMeta(expectedWarehouseUpdate)).
+						WithArgs(row[2], 1, row[1]).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+						WithArgs(row[1], 1, row[2], model.MovementCancel, reservationId).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectCommit()
+			},
+			wantReply: model.CancelReservationResp{
+				ReservationId: reservationId,
+				Status:        model.ReservationReleased,
+				CancelledProductsInfo: []model.CancelledProductResp{
+					{Code: "12345", WarehouseId: 1, Quantity: 3},
+					{Code: "12345", WarehouseId: 2, Quantity: 2},
+				},
+			},
+		},
+		{
+			name: "Committed",
+			mockBehavior: func(reservationId string) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedStatusesQuery)).
+					WithArgs(reservationId).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("committed"))
+				mock.ExpectRollback()
+			},
+			wantErr: model.ErrReservationCommitted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(reservationId)
+
+			var reply model.CancelReservationResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.CancelReservation(req, &model.CancelReservationReq{ReservationId: reservationId}, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
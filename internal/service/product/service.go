@@ -2,7 +2,11 @@ package product
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -17,54 +21,110 @@ import (
 )
 
 const (
-	rejected = "rejected: "
-	reserved = "reserved"
-	released = "released"
-	GOLIMIT  = 10
+	rejected          = "rejected: "
+	reserved          = "reserved"
+	partiallyReserved = "partially_reserved"
+	released          = "released"
+
+	goroutinesLimit = 10
 )
 
-type Service struct {
-	repo      repository.Repository
-	txManager dbutil.TxManager
+// reservationInfo describes the reservation all lines of a ReserveProducts call belong to
+type reservationInfo struct {
+	id           string
+	ttlSeconds   int
+	strategy     AllocationStrategy
+	allowPartial bool
 }
 
-func NewService(repo repository.Repository, txManager dbutil.TxManager) service.ProductService {
-	return &Service{
-		repo:      repo,
-		txManager: txManager,
-	}
+type Config interface {
+	GetDefaultStrategy() string
 }
 
-/*
-
-wg.Add(1)
-go func() {
-	for product := range productsChan {
-		wg.Add(1)
-		go work.......
+type Service struct {
+	repo            repository.Repository
+	txManager       dbutil.TxManager
+	defaultStrategy model.AllocationStrategyName
+}
 
+func NewService(repo repository.Repository, txManager dbutil.TxManager, cfg Config) service.ProductService {
+	defaultStrategy := model.AllocationStrategyName(cfg.GetDefaultStrategy())
+	if defaultStrategy == "" {
+		defaultStrategy = model.StrategyLargestStockFirst
 	}
-}()
 
-*/
+	return &Service{
+		repo:            repo,
+		txManager:       txManager,
+		defaultStrategy: defaultStrategy,
+	}
+}
 
 func (s *Service) ReserveProducts(r *http.Request, args *model.ReserveProductsReq, reply *model.ReserveProductsResp) error {
+	return s.withIdempotency(r.Context(), reserveProductsMethod, args.IdempotencyKey, args, reply, func(ctx context.Context) error {
+		return s.reserveProducts(ctx, args, reply)
+	})
+}
+
+func (s *Service) reserveProducts(ctx context.Context, args *model.ReserveProductsReq, reply *model.ReserveProductsResp) error {
 	var (
 		products        = args.Products
 		productsInfo    []model.ReserveProductResp
 		wg              sync.WaitGroup
-		outputCh               = make(chan model.ReserveProductResp)
-		reservationId   string = uuid.New().String()
+		outputCh        = make(chan model.ReserveProductResp)
+		reservation     = reservationInfo{id: uuid.New().String(), ttlSeconds: args.TTLSeconds, allowPartial: args.AllowPartial}
 		goroutinesCount int
 	)
 
-	if len(products) == 0 {
+	if len(products) == 0 || args.TTLSeconds < 0 || args.TTLSeconds > model.MaxTTLSeconds {
 		*reply = model.ReserveProductsResp{}
 		return model.ErrInvalidInput
 	}
 
-	if len(products) > GOLIMIT {
-		goroutinesCount = GOLIMIT
+	strategyName := args.Strategy
+	if strategyName == "" {
+		strategyName = s.defaultStrategy
+	}
+
+	strategy, err := newAllocationStrategy(strategyName, args)
+	if err != nil {
+		*reply = model.ReserveProductsResp{}
+		return err
+	}
+	reservation.strategy = strategy
+
+	if args.Atomic {
+		productsInfo, err = s.processAtomicReservation(ctx, products, reservation)
+		if err != nil {
+			return err
+		}
+
+		*reply = model.ReserveProductsResp{
+			ReservationId:           reservation.id,
+			ReservationProductsInfo: productsInfo,
+		}
+		return nil
+	}
+
+	if dbutil.InTx(ctx) {
+		// The lines join the transaction of the caller, which runs one query at a time
+		for _, product := range products {
+			line, err := s.reserveLine(ctx, product, reservation)
+			if err != nil {
+				return err
+			}
+			productsInfo = append(productsInfo, line)
+		}
+
+		*reply = model.ReserveProductsResp{
+			ReservationId:           reservation.id,
+			ReservationProductsInfo: productsInfo,
+		}
+		return nil
+	}
+
+	if len(products) > goroutinesLimit {
+		goroutinesCount = goroutinesLimit
 	} else {
 		goroutinesCount = len(products)
 	}
@@ -76,7 +136,7 @@ func (s *Service) ReserveProducts(r *http.Request, args *model.ReserveProductsRe
 	go func() {
 		for product := range productsChan {
 			wg.Add(1)
-			go s.processReservation(r.Context(), outputCh, &wg, product, reservationId)
+			go s.processReservation(ctx, outputCh, &wg, product, reservation)
 		}
 		wg.Wait() // nyjen li wg Wait
 		close(outputCh)
@@ -87,7 +147,7 @@ func (s *Service) ReserveProducts(r *http.Request, args *model.ReserveProductsRe
 	}
 
 	*reply = model.ReserveProductsResp{
-		ReservationId:           reservationId,
+		ReservationId:           reservation.id,
 		ReservationProductsInfo: productsInfo,
 	}
 
@@ -95,81 +155,289 @@ func (s *Service) ReserveProducts(r *http.Request, args *model.ReserveProductsRe
 }
 
 func (s *Service) createReserveProductWork(products []model.ReserveProductReq, productsChan chan<- model.ReserveProductReq) {
+	defer close(productsChan)
+
 	for _, inputProduct := range products {
 		productsChan <- inputProduct
 	}
 }
 
-func (s *Service) startLimitedReserveProduct(ctx context.Context, productsChan chan model.ReserveProductReq, outputCh chan<- model.ReserveProductResp, wg *sync.WaitGroup, product model.ReserveProductReq, reservationId string) {
-	///
-	for product := range productsChan {
-		wg.Add(1)
-		go s.processReservation(ctx, outputCh, wg, product, reservationId)
+func (s *Service) processReservation(ctx context.Context, outputCh chan<- model.ReserveProductResp, wg *sync.WaitGroup, product model.ReserveProductReq, reservation reservationInfo) {
+	defer wg.Done()
+
+	line, err := s.reserveLine(ctx, product, reservation)
+	if err != nil {
+		line = rejectedReservation(line, model.ErrInternalServer)
 	}
-	wg.Wait() // nyjen li wg Wait
-	close(outputCh)
+
+	outputCh <- line
 }
 
-func (s *Service) processReservation(ctx context.Context, outputCh chan<- model.ReserveProductResp, wg *sync.WaitGroup, product model.ReserveProductReq, reservationId string) {
+// reserveLine reserves a line in its own transaction or in the transaction of the caller, a rejected line
+// is returned without error. The error is a retryable one met in the caller's transaction, which
+// has to be run again as a whole.
+func (s *Service) reserveLine(ctx context.Context, product model.ReserveProductReq, reservation reservationInfo) (model.ReserveProductResp, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	defer wg.Done()
-
+	line := model.ReserveProductResp{Code: product.Code, Requested: product.Quantity}
 	if product.Quantity <= 0 {
-		outputCh <- model.ReserveProductResp{Code: product.Code, Status: rejected + model.ErrInvalidInput.Error()}
-		return
+		return rejectedReservation(line, model.ErrInvalidInput), nil
 	}
 
 	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
 		logger.DebugKV(ctx, "Reservation", "info", "Start tx")
-		quantityProductsOnActiveWhs, err := s.repo.GetTotalQuantityOfProducts(ctx, product.Code)
-		if err != nil {
-			logger.DebugKV(ctx, "Reservation", "err", err)
-			return model.ErrInvalidInput
+		var err error
+		line, err = s.reserveProduct(ctx, product, reservation)
+		return err
+	})
+	if dbutil.IsRetryable(err) {
+		if dbutil.InTx(ctx) {
+			return line, err
 		}
-		logger.DebugKV(ctx, "Reservation", "quantityProductsOnActiveWhs", quantityProductsOnActiveWhs)
+		err = model.ErrInternalServer
+	}
 
-		if quantityProductsOnActiveWhs < product.Quantity {
-			logger.DebugKV(ctx, "Reservation", "err", err)
-			return model.ErrInvalidQuantity
+	logger.DebugKV(ctx, "Reservation", "switch", "switch")
+	switch {
+	case ctx.Err() != nil:
+		logger.DebugKV(ctx, "Reservation switch", "ctx.Err() != nil", ctx.Err())
+		return rejectedReservation(line, model.ErrInternalServer), nil
+	case err != nil:
+		logger.DebugKV(ctx, "Reservation switch", "err != nil", err)
+		return rejectedReservation(line, err), nil
+	default:
+		logger.DebugKV(ctx, "Reservation switch", "default", "default")
+		return line, nil
+	}
+}
+
+// processAtomicReservation reserves every line in a single transaction and rejects
+// all of them if any line cannot be satisfied. Lines are processed in order of product
+// code, so concurrent atomic reservations lock warehouse_product rows in the same order.
+// The error is a retryable one met in the transaction of the caller.
+func (s *Service) processAtomicReservation(ctx context.Context, products []model.ReserveProductReq, reservation reservationInfo) ([]model.ReserveProductResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	order := make([]int, len(products))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return products[order[i]].Code < products[order[j]].Code
+	})
+
+	var err error
+	failedIdx := -1
+	lines := make([]model.ReserveProductResp, len(products))
+	for i, product := range products {
+		lines[i] = model.ReserveProductResp{Code: product.Code, Requested: product.Quantity}
+	}
+
+	for _, idx := range order {
+		if products[idx].Quantity <= 0 {
+			failedIdx = idx
+			err = model.ErrInvalidInput
+			break
+		}
+	}
+
+	if err == nil {
+		err = s.txManager.WithTx(ctx, func(ctx context.Context) error {
+			for _, idx := range order {
+				line, err := s.reserveProduct(ctx, products[idx], reservation)
+				lines[idx] = line
+				if err != nil {
+					failedIdx = idx
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	if dbutil.IsRetryable(err) && dbutil.InTx(ctx) {
+		return nil, err
+	}
+	if err != nil && (ctx.Err() != nil || dbutil.IsRetryable(err)) {
+		err = model.ErrInternalServer
+	}
+
+	productsInfo := make([]model.ReserveProductResp, 0, len(products))
+	for i, line := range lines {
+		switch {
+		case err == nil:
+			productsInfo = append(productsInfo, line)
+		case i == failedIdx || failedIdx == -1:
+			productsInfo = append(productsInfo, rejectedReservation(line, err))
+		default:
+			productsInfo = append(productsInfo, rejectedReservation(line, model.ErrFailedReservation))
 		}
+	}
+
+	return productsInfo, nil
+}
+
+// rejectedReservation marks a reservation line as failed with err, nothing of the line stays reserved
+func rejectedReservation(line model.ReserveProductResp, err error) model.ReserveProductResp {
+	line.Status = rejected + err.Error()
+	line.Reserved = 0
+	line.Error = model.NewError(err)
+	return line
+}
 
-		// Get active warehouses sorted by quantity of products with warehouse code
-		productsByWarehouses, err := s.repo.GetProductsByWarehousesByCode(ctx, product.Code)
+// insufficientStockErr reports how many products the line could get
+func insufficientStockErr(pinned bool, available, requested int) error {
+	err := model.ErrInvalidQuantity
+	if pinned {
+		err = model.ErrInsufficientWarehouseStock
+	}
+
+	return model.WithDetails(err, map[string]interface{}{
+		"available": available,
+		"requested": requested,
+	})
+}
+
+// reserveProduct reserves a single line inside the transaction stored in ctx. The returned line
+// reports the available quantity once it is known, even if the line is rejected.
+func (s *Service) reserveProduct(ctx context.Context, product model.ReserveProductReq, reservation reservationInfo) (model.ReserveProductResp, error) {
+	line := model.ReserveProductResp{Code: product.Code, Requested: product.Quantity}
+
+	allowedWarehouses := allowedWarehouses(product)
+	pinned := len(allowedWarehouses) > 0
+	err := s.checkAllowedWarehouses(ctx, allowedWarehouses)
+	if err != nil {
+		logger.DebugKV(ctx, "Reservation", "err", err)
+		return line, err
+	}
+
+	// Get active warehouses holding the product and let the strategy decide which of them to drain,
+	// a pinned line can only get the stock of its warehouses
+	var (
+		productsByWarehouses []repoModel.ProductsOnActiveWarehouse
+		available            int
+	)
+	if pinned {
+		productsByWarehouses, err = s.getProductsByWarehouses(ctx, product.Code, allowedWarehouses)
 		if err != nil {
-			logger.DebugKV(ctx, "Reservation", "err", err)
-			return model.ErrInternalServer
+			return line, err
 		}
-		logger.DebugKV(ctx, "Reservation", "productsByWarehouses", productsByWarehouses)
 
-		logger.DebugKV(ctx, "Reservation", "startReservation", "true")
-		err = s.startReservation(ctx, productsByWarehouses, reservationId, product.Quantity)
+		for _, productsByWarehouse := range productsByWarehouses {
+			available += productsByWarehouse.Quantity
+		}
+	} else {
+		available, err = s.repo.GetTotalQuantityOfProducts(ctx, product.Code)
 		if err != nil {
 			logger.DebugKV(ctx, "Reservation", "err", err)
-			return err
+			return line, repoErr(err, model.ErrInvalidInput)
+		}
+	}
+	logger.DebugKV(ctx, "Reservation", "available", available)
+
+	line.Available = &available
+	if available < product.Quantity && !reservation.allowPartial {
+		return line, insufficientStockErr(pinned, available, product.Quantity)
+	}
+
+	if !pinned {
+		productsByWarehouses, err = s.getProductsByWarehouses(ctx, product.Code, nil)
+		if err != nil {
+			return line, err
+		}
+	}
+
+	quantity := product.Quantity
+	if available < quantity {
+		if !reservation.allowPartial || available == 0 {
+			return line, insufficientStockErr(pinned, available, product.Quantity)
 		}
+		quantity = available
+	}
+	if limiter, ok := reservation.strategy.(capacityLimiter); ok && reservation.allowPartial {
+		quantity = min(quantity, limiter.Capacity(productsByWarehouses))
+	}
+
+	productsByWarehouses, err = reservation.strategy.Allocate(productsByWarehouses, quantity)
+	if err != nil {
+		logger.DebugKV(ctx, "Reservation", "err", err)
+		return line, err
+	}
+
+	logger.DebugKV(ctx, "Reservation", "startReservation", "true")
+	err = s.startReservation(ctx, productsByWarehouses, reservation, quantity)
+	if err != nil {
+		logger.DebugKV(ctx, "Reservation", "err", err)
+		return line, err
+	}
+
+	line.Reserved = quantity
+	line.Status = reserved
+	if quantity < product.Quantity {
+		line.Status = partiallyReserved
+	}
+
+	return line, nil
+}
 
+func (s *Service) getProductsByWarehouses(ctx context.Context, code string, warehouseIds []int) ([]repoModel.ProductsOnActiveWarehouse, error) {
+	productsByWarehouses, err := s.repo.GetProductsByWarehousesByCode(ctx, code, warehouseIds)
+	if err != nil {
+		logger.DebugKV(ctx, "Reservation", "err", err)
+		return nil, repoErr(err, model.ErrInternalServer)
+	}
+	logger.DebugKV(ctx, "Reservation", "productsByWarehouses", productsByWarehouses)
+
+	return productsByWarehouses, nil
+}
+
+// allowedWarehouses returns sorted ids of warehouses the line is pinned to, nil if any warehouse may be used
+func allowedWarehouses(product model.ReserveProductReq) []int {
+	ids := make([]int, 0, len(product.WarehouseIds)+1)
+	ids = append(ids, product.WarehouseIds...)
+	if product.WarehouseId != 0 {
+		ids = append(ids, product.WarehouseId)
+	}
+	if len(ids) == 0 {
 		return nil
-	})
+	}
 
-	logger.DebugKV(ctx, "Reservation", "switch", "switch")
-	switch {
-	case ctx.Err() != nil:
-		logger.DebugKV(ctx, "Reservation switch", "ctx.Err() != nil", ctx.Err())
-		outputCh <- model.ReserveProductResp{Code: product.Code, Status: rejected + model.ErrInternalServer.Error()}
-	case err != nil:
-		logger.DebugKV(ctx, "Reservation switch", "err != nil", err)
-		outputCh <- model.ReserveProductResp{Code: product.Code, Status: rejected + err.Error()}
-	default:
-		logger.DebugKV(ctx, "Reservation switch", "default", "default")
-		outputCh <- model.ReserveProductResp{Code: product.Code, Status: reserved}
+	sort.Ints(ids)
+	unique := ids[:1]
+	for _, id := range ids[1:] {
+		if id != unique[len(unique)-1] {
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
+
+// checkAllowedWarehouses rejects the line if any warehouse it is pinned to is missing or unavailable
+func (s *Service) checkAllowedWarehouses(ctx context.Context, warehouseIds []int) error {
+	for _, warehouseId := range warehouseIds {
+		isAvailable, err := s.repo.GetWarehouseAvailabilityById(ctx, warehouseId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %d", model.ErrWarehouseNotFound, warehouseId)
+			}
+			return repoErr(err, model.ErrInternalServer)
+		}
+
+		if !isAvailable {
+			return fmt.Errorf("%w: %d", model.ErrWarehouseUnavailable, warehouseId)
+		}
 	}
+
+	return nil
 }
 
-func (s *Service) startReservation(ctx context.Context, productsByWarehouses []repoModel.ProductsOnActiveWarehouse, reservationId string, quantity int) error {
+func (s *Service) startReservation(ctx context.Context, productsByWarehouses []repoModel.ProductsOnActiveWarehouse, reservation reservationInfo, quantity int) error {
 	var err error
-	// Begin reserving products from warehouses, starting from the warehouse with the maximum values
+	// Begin reserving products from warehouses in the order chosen by the allocation strategy
 	for _, productsByWarehouse := range productsByWarehouses {
 		var quantityLeftOnWarehouse, quantityForReservation int
 
@@ -185,16 +453,30 @@ func (s *Service) startReservation(ctx context.Context, productsByWarehouses []r
 
 		err = s.repo.UpdateWarehouseQuantity(ctx, productsByWarehouse.WarehouseId, productsByWarehouse.ProductId, quantityLeftOnWarehouse)
 		if err != nil {
-			err = model.ErrInternalServer
+			err = repoErr(err, model.ErrInternalServer)
 			break
 		}
 
-		_, err = s.repo.CreateReservation(ctx, productsByWarehouse.WarehouseId, productsByWarehouse.ProductId, quantityForReservation, reservationId)
+		_, err = s.repo.CreateReservation(ctx, productsByWarehouse.WarehouseId, productsByWarehouse.ProductId, quantityForReservation, reservation.id, reservation.ttlSeconds)
 		if err != nil {
-			err = model.ErrInternalServer
+			err = repoErr(err, model.ErrInternalServer)
 			break
 		}
 
+		if quantityForReservation > 0 {
+			err = s.repo.CreateStockMovement(ctx, repoModel.StockMovement{
+				WarehouseId: productsByWarehouse.WarehouseId,
+				ProductId:   productsByWarehouse.ProductId,
+				Delta:       -quantityForReservation,
+				Reason:      model.MovementReserve,
+				ReferenceId: reservation.id,
+			})
+			if err != nil {
+				err = repoErr(err, model.ErrInternalServer)
+				break
+			}
+		}
+
 		if quantity == 0 {
 			break
 		}
@@ -203,6 +485,12 @@ func (s *Service) startReservation(ctx context.Context, productsByWarehouses []r
 }
 
 func (s *Service) ReleaseProducts(r *http.Request, args *model.ReleaseProductsReq, reply *model.ReleaseProductsResp) error {
+	return s.withIdempotency(r.Context(), releaseProductsMethod, args.IdempotencyKey, args, reply, func(ctx context.Context) error {
+		return s.releaseProducts(ctx, args, reply)
+	})
+}
+
+func (s *Service) releaseProducts(ctx context.Context, args *model.ReleaseProductsReq, reply *model.ReleaseProductsResp) error {
 	var (
 		products     = args.Products
 		productsInfo []model.ReleaseProductResp
@@ -216,6 +504,20 @@ func (s *Service) ReleaseProducts(r *http.Request, args *model.ReleaseProductsRe
 		return model.ErrInvalidInput
 	}
 
+	if dbutil.InTx(ctx) {
+		// The lines join the transaction of the caller, which runs one query at a time
+		for _, product := range products {
+			line, err := s.releaseLine(ctx, product)
+			if err != nil {
+				return err
+			}
+			productsInfo = append(productsInfo, line)
+		}
+
+		*reply = model.ReleaseProductsResp{ReleaseProductsInfo: productsInfo}
+		return nil
+	}
+
 	if len(products) > goroutinesLimit {
 		sema = semaphore.New(goroutinesLimit)
 	} else {
@@ -225,7 +527,7 @@ func (s *Service) ReleaseProducts(r *http.Request, args *model.ReleaseProductsRe
 	go func() {
 		for _, product := range products {
 			wg.Add(1)
-			go s.processRelease(r.Context(), outputCh, &wg, sema, product)
+			go s.processRelease(ctx, outputCh, &wg, sema, product)
 		}
 
 		wg.Wait()
@@ -241,47 +543,74 @@ func (s *Service) ReleaseProducts(r *http.Request, args *model.ReleaseProductsRe
 }
 
 func (s *Service) processRelease(ctx context.Context, outputCh chan<- model.ReleaseProductResp, wg *sync.WaitGroup, sema *semaphore.Semaphore, product model.ReleaseProductReq) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
 	defer wg.Done()
 
 	sema.Acquire()
 	defer sema.Release()
 
+	line, err := s.releaseLine(ctx, product)
+	if err != nil {
+		line = rejectedRelease(product, model.ErrInternalServer)
+	}
+
+	outputCh <- line
+}
+
+// releaseLine releases a line the same way reserveLine reserves one
+func (s *Service) releaseLine(ctx context.Context, product model.ReleaseProductReq) (model.ReleaseProductResp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
 	if product.Quantity <= 0 {
-		outputCh <- model.ReleaseProductResp{ReservationId: product.ReservationId, Code: product.Code, Status: rejected + model.ErrInvalidInput.Error()}
-		return
+		return rejectedRelease(product, model.ErrInvalidInput), nil
 	}
 
 	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		err := s.checkReservationStatus(ctx, product.ReservationId, model.ReservationReleased)
+		if err != nil {
+			return err
+		}
+
 		quantityProductsInReservation, err := s.repo.GetTotalQuantityOfReservation(ctx, product.ReservationId, product.Code)
 		if err != nil {
-			return model.ErrInvalidInput
+			return repoErr(err, model.ErrInvalidInput)
 		}
 
 		if quantityProductsInReservation < product.Quantity {
-			return model.ErrInvalidReservationQuantity
+			return model.WithDetails(model.ErrInvalidReservationQuantity, map[string]interface{}{
+				"reserved":  quantityProductsInReservation,
+				"requested": product.Quantity,
+			})
 		}
 
 		productsByWarehousesInReservation, err := s.repo.GetProductsByReservationByIdAndCode(ctx, product.ReservationId, product.Code)
 		if err != nil {
-			return model.ErrInvalidInput
+			return repoErr(err, model.ErrInvalidInput)
 		}
 
-		s.startRelease(ctx, productsByWarehousesInReservation, product.Quantity)
-
-		return nil
+		return s.startRelease(ctx, productsByWarehousesInReservation, product.Quantity, model.MovementRelease)
 	})
+	if dbutil.IsRetryable(err) {
+		if dbutil.InTx(ctx) {
+			return model.ReleaseProductResp{}, err
+		}
+		err = model.ErrInternalServer
+	}
 
 	if err != nil {
-		outputCh <- model.ReleaseProductResp{ReservationId: product.ReservationId, Code: product.Code, Status: rejected + err.Error()}
-	} else {
-		outputCh <- model.ReleaseProductResp{ReservationId: product.ReservationId, Code: product.Code, Status: released}
+		return rejectedRelease(product, err), nil
 	}
+	return model.ReleaseProductResp{ReservationId: product.ReservationId, Code: product.Code, Status: released}, nil
+}
+
+// rejectedRelease describes a release line failed with err
+func rejectedRelease(product model.ReleaseProductReq, err error) model.ReleaseProductResp {
+	return model.ReleaseProductResp{ReservationId: product.ReservationId, Code: product.Code, Status: rejected + err.Error(), Error: model.NewError(err)}
 }
 
-func (s *Service) startRelease(ctx context.Context, productsByWarehousesInReservation []repoModel.ProductsInReservation, quantity int) error {
+// startRelease returns quantity of products held by the reservation lines to warehouses.
+// Reason is written to the stock ledger: release, cancel or expire.
+func (s *Service) startRelease(ctx context.Context, productsByWarehousesInReservation []repoModel.ProductsInReservation, quantity int, reason model.MovementReason) error {
 	var err error
 	for _, productsByWarehouseInResevation := range productsByWarehousesInReservation {
 		var remainInReservation, addToWarehouse int
@@ -301,20 +630,42 @@ func (s *Service) startRelease(ctx context.Context, productsByWarehousesInReserv
 			remainInReservation,
 		)
 		if err != nil {
-			err = model.ErrInternalServer
+			err = repoErr(err, model.ErrInternalServer)
 			break
 		}
 
+		if remainInReservation == 0 {
+			err = s.repo.UpdateReservationStatus(ctx, productsByWarehouseInResevation.ID, model.ReservationReleased)
+			if err != nil {
+				err = repoErr(err, model.ErrInternalServer)
+				break
+			}
+		}
+
 		err = s.repo.UpdateWarehouseQuantityWithAdd(ctx,
 			productsByWarehouseInResevation.WarehouseId,
 			productsByWarehouseInResevation.ProductId,
 			addToWarehouse,
 		)
 		if err != nil {
-			err = model.ErrInternalServer
+			err = repoErr(err, model.ErrInternalServer)
 			break
 		}
 
+		if addToWarehouse > 0 {
+			err = s.repo.CreateStockMovement(ctx, repoModel.StockMovement{
+				WarehouseId: productsByWarehouseInResevation.WarehouseId,
+				ProductId:   productsByWarehouseInResevation.ProductId,
+				Delta:       addToWarehouse,
+				Reason:      reason,
+				ReferenceId: productsByWarehouseInResevation.ReservationId,
+			})
+			if err != nil {
+				err = repoErr(err, model.ErrInternalServer)
+				break
+			}
+		}
+
 		if quantity == 0 {
 			break
 		}
@@ -322,10 +673,26 @@ func (s *Service) startRelease(ctx context.Context, productsByWarehousesInReserv
 	return err
 }
 
+// repoErr hides a repository error behind modelErr, except serialization failures
+// which have to reach the transaction manager to be retried
+func repoErr(err, modelErr error) error {
+	if dbutil.IsRetryable(err) {
+		return err
+	}
+
+	return modelErr
+}
+
 func (s *Service) GetProductsByWarehouse(r *http.Request, args *model.ShowProductsReq, reply *[]model.Product) error {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	products, err := s.repo.GetProductsByWarehouseId(ctx, args.WarehouseId)
+
+	var products []model.Product
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		products, err = s.repo.GetProductsByWarehouseId(ctx, args.WarehouseId)
+		return err
+	}, dbutil.WithIsolation(sql.LevelReadCommitted), dbutil.WithReadOnly())
 	if err != nil {
 		return err
 	}
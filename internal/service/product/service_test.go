@@ -0,0 +1,541 @@
+package product
+
+import (
+	"errors"
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type txConfig struct{}
+
+func (txConfig) GetMaxRetries() int {
+	return 0
+}
+
+func (txConfig) GetRetryBaseDelay() time.Duration {
+	return 0
+}
+
+func (txConfig) GetRetryMaxDelay() time.Duration {
+	return 0
+}
+
+func (txConfig) GetDefaultStrategy() string {
+	return ""
+}
+
+func TestReserveProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}), txConfig{})
+
+	type args struct {
+		code         string
+		quantity     int
+		strategy     model.AllocationStrategyName
+		warehouseId  int
+		allowPartial bool
+	}
+
+	type mockBehavior func(args args)
+
+	expectedTotalQuery := "WITH total_products AS"
+	expectedWarehousesQuery := "SELECT wp.warehouse_id, wp.product_id, wp.quantity, w.priority, w.region, w.latitude, w.longitude FROM warehouse_product wp"
+	expectedUpdateQuery := "UPDATE warehouse_product SET quantity = $1 WHERE product_id = $2 AND warehouse_id = $3"
+	expectedInsertQuery := "INSERT INTO reservation (reservation_id,warehouse_id,product_id,quantity) VALUES ($1,$2,$3,$4) RETURNING id"
+	expectedMovementQuery := "INSERT INTO stock_movement (warehouse_id,product_id,delta,reason,reference_id) VALUES ($1,$2,$3,$4,$5)"
+	expectedAvailabilityQuery := "SELECT availability FROM warehouse WHERE id = $1"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantStatus   string
+		wantReserved int
+		wantError    *model.Error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(6))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+						AddRow(1, 1, 3, 0, "", nil, nil).
+						AddRow(2, 1, 3, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(0, 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 1, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(1, 1, -3, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(2, 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 2, 1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(2, 1, -1, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			args: args{
+				code:     "12345",
+				quantity: 4,
+			},
+			wantStatus:   reserved,
+			wantReserved: 4,
+		},
+		{
+			name: "Rollback on failed reservation insert",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(6))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+						AddRow(1, 1, 3, 0, "", nil, nil).
+						AddRow(2, 1, 3, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(0, 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 1, 1, 3).
+					WillReturnError(errors.New("any error"))
+				mock.ExpectRollback()
+			},
+			args: args{
+				code:     "12345",
+				quantity: 4,
+			},
+			wantStatus: rejected + model.ErrInternalServer.Error(),
+		},
+		{
+			name: "Rollback on failed warehouse update",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(6))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+						AddRow(1, 1, 3, 0, "", nil, nil).
+						AddRow(2, 1, 3, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(0, 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 1, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(1, 1, -3, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(2, 1, 2).
+					WillReturnError(errors.New("any error"))
+				mock.ExpectRollback()
+			},
+			args: args{
+				code:     "12345",
+				quantity: 4,
+			},
+			wantStatus: rejected + model.ErrInternalServer.Error(),
+		},
+		{
+			name: "Fewest warehouses takes the smallest sufficient stock",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(10))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+						AddRow(1, 1, 6, 0, "", nil, nil).
+						AddRow(2, 1, 4, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(0, 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 2, 1, 4).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(2, 1, -4, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			args: args{
+				code:     "12345",
+				quantity: 4,
+				strategy: model.StrategyFewestWarehouses,
+			},
+			wantStatus:   reserved,
+			wantReserved: 4,
+		},
+		{
+			name: "Single warehouse rejected when stock is split",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(6))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+						AddRow(1, 1, 3, 0, "", nil, nil).
+						AddRow(2, 1, 3, 0, "", nil, nil))
+				mock.ExpectRollback()
+			},
+			args: args{
+				code:     "12345",
+				quantity: 4,
+				strategy: model.StrategySingleWarehouse,
+			},
+			wantStatus: rejected + model.ErrNoSingleWarehouse.Error(),
+		},
+		{
+			name: "Pinned to warehouse",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedAvailabilityQuery)).
+					WithArgs(args.warehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"availability"}).AddRow(true))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true, args.warehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+						AddRow(2, 1, 4, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(0, 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 2, 1, 4).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(2, 1, -4, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			args: args{
+				code:        "12345",
+				quantity:    4,
+				warehouseId: 2,
+			},
+			wantStatus:   reserved,
+			wantReserved: 4,
+		},
+		{
+			name: "Pinned warehouse unavailable",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedAvailabilityQuery)).
+					WithArgs(args.warehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"availability"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+			args: args{
+				code:        "12345",
+				quantity:    4,
+				warehouseId: 3,
+			},
+			wantStatus: rejected + model.ErrWarehouseUnavailable.Error() + ": 3",
+		},
+		{
+			name: "Pinned warehouse short",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedAvailabilityQuery)).
+					WithArgs(args.warehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"availability"}).AddRow(true))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true, args.warehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+						AddRow(2, 1, 3, 0, "", nil, nil))
+				mock.ExpectRollback()
+			},
+			args: args{
+				code:        "12345",
+				quantity:    4,
+				warehouseId: 2,
+			},
+			wantStatus: rejected + model.ErrInsufficientWarehouseStock.Error(),
+			wantError: &model.Error{
+				Code:    model.CodeInsufficientStock,
+				Message: model.ErrInsufficientWarehouseStock.Error(),
+				Details: map[string]interface{}{"available": 3, "requested": 4},
+			},
+		},
+		{
+			name: "Partial reservation",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(3))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+						AddRow(1, 1, 3, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(0, 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 1, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(1, 1, -3, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			args: args{
+				code:         "12345",
+				quantity:     5,
+				allowPartial: true,
+			},
+			wantStatus:   partiallyReserved,
+			wantReserved: 3,
+		},
+		{
+			name: "Partial reservation from a single warehouse",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(5))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).
+						AddRow(1, 1, 2, 0, "", nil, nil).
+						AddRow(2, 1, 3, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(0, 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 2, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(2, 1, -3, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			args: args{
+				code:         "12345",
+				quantity:     4,
+				strategy:     model.StrategySingleWarehouse,
+				allowPartial: true,
+			},
+			wantStatus:   partiallyReserved,
+			wantReserved: 3,
+		},
+		{
+			name: "Partial reservation without stock",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs(args.code).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs(args.code, true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}))
+				mock.ExpectRollback()
+			},
+			args: args{
+				code:         "12345",
+				quantity:     5,
+				allowPartial: true,
+			},
+			wantStatus: rejected + model.ErrInvalidQuantity.Error(),
+			wantError: &model.Error{
+				Code:    model.CodeInsufficientStock,
+				Message: model.ErrInvalidQuantity.Error(),
+				Details: map[string]interface{}{"available": 0, "requested": 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			var reply model.ReserveProductsResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.ReserveProducts(req, &model.ReserveProductsReq{
+				Products:     []model.ReserveProductReq{{Code: tt.args.code, Quantity: tt.args.quantity, WarehouseId: tt.args.warehouseId}},
+				Strategy:     tt.args.strategy,
+				AllowPartial: tt.args.allowPartial,
+			}, &reply)
+
+			assert.NoError(t, err)
+			assert.Len(t, reply.ReservationProductsInfo, 1)
+			assert.Equal(t, tt.wantStatus, reply.ReservationProductsInfo[0].Status)
+			assert.Equal(t, tt.args.quantity, reply.ReservationProductsInfo[0].Requested)
+			assert.Equal(t, tt.wantReserved, reply.ReservationProductsInfo[0].Reserved)
+			if tt.wantError != nil {
+				gotError := reply.ReservationProductsInfo[0].Error
+				if assert.NotNil(t, gotError) {
+					assert.Equal(t, tt.wantError.Code, gotError.Code)
+					assert.Equal(t, tt.wantError.Message, gotError.Message)
+					assert.Equal(t, tt.wantError.Details, gotError.Details)
+				}
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReserveProductsAtomic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}), txConfig{})
+
+	type mockBehavior func()
+
+	expectedTotalQuery := "WITH total_products AS"
+	expectedWarehousesQuery := "SELECT wp.warehouse_id, wp.product_id, wp.quantity, w.priority, w.region, w.latitude, w.longitude FROM warehouse_product wp"
+	expectedUpdateQuery := "UPDATE warehouse_product SET quantity = $1 WHERE product_id = $2 AND warehouse_id = $3"
+	expectedInsertQuery := "INSERT INTO reservation (reservation_id,warehouse_id,product_id,quantity) VALUES ($1,$2,$3,$4) RETURNING id"
+	expectedMovementQuery := "INSERT INTO stock_movement (warehouse_id,product_id,delta,reason,reference_id) VALUES ($1,$2,$3,$4,$5)"
+
+	products := []model.ReserveProductReq{
+		{Code: "12346", Quantity: 2},
+		{Code: "12345", Quantity: 1},
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		products     []model.ReserveProductReq
+		wantStatuses []string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs("12345").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(3))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs("12345", true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).AddRow(1, 1, 3, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(2, 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 1, 1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(1, 1, -1, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs("12346").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(5))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs("12346", true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).AddRow(1, 2, 5, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(3, 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 1, 2, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(1, 2, -2, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			products:     products,
+			wantStatuses: []string{reserved, reserved},
+		},
+		{
+			name: "Rollback when one line is short",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs("12345").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(3))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedWarehousesQuery)).
+					WithArgs("12345", true).
+					WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "product_id", "quantity", "priority", "region", "latitude", "longitude"}).AddRow(1, 1, 3, 0, "", nil, nil))
+				mock.ExpectExec(regexp.QuoteMeta(expectedUpdateQuery)).
+					WithArgs(2, 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedInsertQuery)).
+					WithArgs(sqlmock.AnyArg(), 1, 1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(expectedMovementQuery)).
+					WithArgs(1, 1, -1, model.MovementReserve, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTotalQuery)).
+					WithArgs("12346").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			products: products,
+			wantStatuses: []string{
+				rejected + model.ErrInvalidQuantity.Error(),
+				rejected + model.ErrFailedReservation.Error(),
+			},
+		},
+		{
+			name:         "Invalid quantity",
+			mockBehavior: func() {},
+			products: []model.ReserveProductReq{
+				{Code: "12346", Quantity: 2},
+				{Code: "12345", Quantity: 0},
+			},
+			wantStatuses: []string{
+				rejected + model.ErrFailedReservation.Error(),
+				rejected + model.ErrInvalidInput.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			var reply model.ReserveProductsResp
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.ReserveProducts(req, &model.ReserveProductsReq{Products: tt.products, Atomic: true}, &reply)
+
+			assert.NoError(t, err)
+			gotStatuses := make([]string, 0, len(reply.ReservationProductsInfo))
+			for _, info := range reply.ReservationProductsInfo {
+				gotStatuses = append(gotStatuses, info.Status)
+			}
+			assert.Equal(t, tt.wantStatuses, gotStatuses)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
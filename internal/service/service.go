@@ -9,5 +9,31 @@ import (
 type ProductService interface {
 	ReserveProducts(r *http.Request, args *model.ReserveProductsReq, reply *model.ReserveProductsResp) error
 	ReleaseProducts(r *http.Request, args *model.ReleaseProductsReq, reply *model.ReleaseProductsResp) error
+	CommitReservation(r *http.Request, args *model.CommitReservationReq, reply *model.CommitReservationResp) error
+	CancelReservation(r *http.Request, args *model.CancelReservationReq, reply *model.CancelReservationResp) error
+	GetReservation(r *http.Request, args *model.GetReservationReq, reply *model.GetReservationResp) error
 	GetProductsByWarehouse(r *http.Request, args *model.ShowProductsReq, reply *[]model.Product) error
 }
+
+type WarehouseService interface {
+	CreateWarehouse(r *http.Request, args *model.CreateWarehouseReq, reply *model.Warehouse) error
+	UpdateWarehouse(r *http.Request, args *model.UpdateWarehouseReq, reply *model.Warehouse) error
+	SetWarehouseAvailability(r *http.Request, args *model.SetWarehouseAvailabilityReq, reply *model.Warehouse) error
+	SetWarehouseRouting(r *http.Request, args *model.SetWarehouseRoutingReq, reply *model.Warehouse) error
+	ListWarehouses(r *http.Request, args *model.ListWarehousesReq, reply *[]model.Warehouse) error
+}
+
+type CatalogService interface {
+	CreateProduct(r *http.Request, args *model.CreateProductReq, reply *model.CatalogProduct) error
+	UpdateProduct(r *http.Request, args *model.UpdateProductReq, reply *model.CatalogProduct) error
+	GetProductByCode(r *http.Request, args *model.GetProductByCodeReq, reply *model.CatalogProduct) error
+	ListProducts(r *http.Request, args *model.ListProductsReq, reply *model.ListProductsResp) error
+}
+
+type InventoryService interface {
+	ReceiveStock(r *http.Request, args *model.ReceiveStockReq, reply *model.ReceiveStockResp) error
+	TransferStock(r *http.Request, args *model.TransferStockReq, reply *model.Transfer) error
+	ReceiveTransfer(r *http.Request, args *model.ReceiveTransferReq, reply *model.Transfer) error
+	GetStockMovements(r *http.Request, args *model.GetStockMovementsReq, reply *model.GetStockMovementsResp) error
+	AdjustStock(r *http.Request, args *model.AdjustStockReq, reply *model.AdjustStockResp) error
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"net/http"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+// CatalogService validates requests before they reach the wrapped service
+type CatalogService struct {
+	next service.CatalogService
+}
+
+func NewCatalogService(next service.CatalogService) *CatalogService {
+	return &CatalogService{next: next}
+}
+
+func (s *CatalogService) CreateProduct(r *http.Request, args *model.CreateProductReq, reply *model.CatalogProduct) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.CreateProduct(r, args, reply)
+}
+
+func (s *CatalogService) UpdateProduct(r *http.Request, args *model.UpdateProductReq, reply *model.CatalogProduct) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.UpdateProduct(r, args, reply)
+}
+
+func (s *CatalogService) GetProductByCode(r *http.Request, args *model.GetProductByCodeReq, reply *model.CatalogProduct) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.GetProductByCode(r, args, reply)
+}
+
+func (s *CatalogService) ListProducts(r *http.Request, args *model.ListProductsReq, reply *model.ListProductsResp) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.ListProducts(r, args, reply)
+}
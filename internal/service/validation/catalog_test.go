@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type catalogServiceStub struct {
+	service.CatalogService
+	calls int
+}
+
+func (s *catalogServiceStub) GetProductByCode(_ *http.Request, args *model.GetProductByCodeReq, reply *model.CatalogProduct) error {
+	s.calls++
+	*reply = model.CatalogProduct{ID: 1, Code: args.Code}
+	return nil
+}
+
+func TestCatalogService(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "Valid request reaches the service",
+			code:      "777",
+			wantCalls: 1,
+		},
+		{
+			name:    "Invalid request is rejected",
+			code:    "",
+			wantErr: model.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &catalogServiceStub{}
+			s := NewCatalogService(next)
+			reply := new(model.CatalogProduct)
+
+			err := s.GetProductByCode(httptest.NewRequest(http.MethodPost, "/rpc", nil), &model.GetProductByCodeReq{Code: tt.code}, reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.code, reply.Code)
+			}
+			assert.Equal(t, tt.wantCalls, next.calls)
+		})
+	}
+}
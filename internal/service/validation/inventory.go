@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"net/http"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+// InventoryService validates requests before they reach the wrapped service
+type InventoryService struct {
+	next service.InventoryService
+}
+
+func NewInventoryService(next service.InventoryService) *InventoryService {
+	return &InventoryService{next: next}
+}
+
+func (s *InventoryService) ReceiveStock(r *http.Request, args *model.ReceiveStockReq, reply *model.ReceiveStockResp) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.ReceiveStock(r, args, reply)
+}
+
+func (s *InventoryService) TransferStock(r *http.Request, args *model.TransferStockReq, reply *model.Transfer) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.TransferStock(r, args, reply)
+}
+
+func (s *InventoryService) ReceiveTransfer(r *http.Request, args *model.ReceiveTransferReq, reply *model.Transfer) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.ReceiveTransfer(r, args, reply)
+}
+
+func (s *InventoryService) GetStockMovements(r *http.Request, args *model.GetStockMovementsReq, reply *model.GetStockMovementsResp) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.GetStockMovements(r, args, reply)
+}
+
+func (s *InventoryService) AdjustStock(r *http.Request, args *model.AdjustStockReq, reply *model.AdjustStockResp) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.AdjustStock(r, args, reply)
+}
@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type inventoryServiceStub struct {
+	service.InventoryService
+	calls int
+}
+
+func (s *inventoryServiceStub) TransferStock(_ *http.Request, args *model.TransferStockReq, reply *model.Transfer) error {
+	s.calls++
+	*reply = model.Transfer{ID: 1, Code: args.Code, Quantity: args.Quantity}
+	return nil
+}
+
+func TestInventoryService(t *testing.T) {
+	tests := []struct {
+		name      string
+		quantity  int
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "Valid request reaches the service",
+			quantity:  2,
+			wantCalls: 1,
+		},
+		{
+			name:     "Invalid request is rejected",
+			quantity: 0,
+			wantErr:  model.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &inventoryServiceStub{}
+			s := NewInventoryService(next)
+			reply := new(model.Transfer)
+
+			args := &model.TransferStockReq{Code: "12345", FromWarehouseId: 1, ToWarehouseId: 2, Quantity: tt.quantity}
+			err := s.TransferStock(httptest.NewRequest(http.MethodPost, "/rpc", nil), args, reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.quantity, reply.Quantity)
+			}
+			assert.Equal(t, tt.wantCalls, next.calls)
+		})
+	}
+}
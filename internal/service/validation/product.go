@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"net/http"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+// ProductService validates requests before they reach the wrapped service
+type ProductService struct {
+	next service.ProductService
+}
+
+func NewProductService(next service.ProductService) *ProductService {
+	return &ProductService{next: next}
+}
+
+func (s *ProductService) ReserveProducts(r *http.Request, args *model.ReserveProductsReq, reply *model.ReserveProductsResp) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.ReserveProducts(r, args, reply)
+}
+
+func (s *ProductService) ReleaseProducts(r *http.Request, args *model.ReleaseProductsReq, reply *model.ReleaseProductsResp) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.ReleaseProducts(r, args, reply)
+}
+
+func (s *ProductService) CommitReservation(r *http.Request, args *model.CommitReservationReq, reply *model.CommitReservationResp) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.CommitReservation(r, args, reply)
+}
+
+func (s *ProductService) CancelReservation(r *http.Request, args *model.CancelReservationReq, reply *model.CancelReservationResp) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.CancelReservation(r, args, reply)
+}
+
+func (s *ProductService) GetReservation(r *http.Request, args *model.GetReservationReq, reply *model.GetReservationResp) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.GetReservation(r, args, reply)
+}
+
+func (s *ProductService) GetProductsByWarehouse(r *http.Request, args *model.ShowProductsReq, reply *[]model.Product) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.GetProductsByWarehouse(r, args, reply)
+}
@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type productServiceStub struct {
+	service.ProductService
+	calls int
+}
+
+func (s *productServiceStub) GetReservation(_ *http.Request, args *model.GetReservationReq, reply *model.GetReservationResp) error {
+	s.calls++
+	*reply = model.GetReservationResp{ReservationId: args.ReservationId, Status: model.ReservationReserved}
+	return nil
+}
+
+func TestProductService(t *testing.T) {
+	tests := []struct {
+		name          string
+		reservationId string
+		wantCalls     int
+		wantErr       error
+	}{
+		{
+			name:          "Valid request reaches the service",
+			reservationId: "8e2c1d6a-1f0b-4f52-9d38-7b2d5a0f6c11",
+			wantCalls:     1,
+		},
+		{
+			name:          "Invalid request is rejected",
+			reservationId: "42",
+			wantErr:       model.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &productServiceStub{}
+			s := NewProductService(next)
+			reply := new(model.GetReservationResp)
+
+			err := s.GetReservation(httptest.NewRequest(http.MethodPost, "/rpc", nil), &model.GetReservationReq{ReservationId: tt.reservationId}, reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.reservationId, reply.ReservationId)
+			}
+			assert.Equal(t, tt.wantCalls, next.calls)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+const (
+	maxBatchSize          = 100
+	maxCodeLength         = 25
+	maxRegionLength       = 32
+	maxNameLength         = 25
+	maxSupplierRefLength  = 64
+	maxProductsLimit      = 100
+	maxStockMovementLimit = 1000
+)
+
+// Validate checks all fields of a request and returns model.ErrValidation with every violation found,
+// requests without rules are valid
+func Validate(args interface{}) error {
+	var v model.Violations
+
+	switch args := args.(type) {
+	case *model.ReserveProductsReq:
+		validateReserveProducts(&v, args)
+	case *model.ReleaseProductsReq:
+		validateReleaseProducts(&v, args)
+	case *model.CommitReservationReq:
+		validateReservationId(&v, "reservation_id", args.ReservationId)
+	case *model.CancelReservationReq:
+		validateReservationId(&v, "reservation_id", args.ReservationId)
+	case *model.GetReservationReq:
+		validateReservationId(&v, "reservation_id", args.ReservationId)
+	case *model.ShowProductsReq:
+		validatePositive(&v, "warehouse_id", args.WarehouseId)
+	case *model.CreateWarehouseReq:
+		validateText(&v, "name", args.Name, maxNameLength)
+		validateRouting(&v, args.Region, args.Location)
+	case *model.UpdateWarehouseReq:
+		validateText(&v, "name", args.Name, maxNameLength)
+	case *model.SetWarehouseRoutingReq:
+		validateRouting(&v, args.Region, args.Location)
+	case *model.CreateProductReq:
+		validateCatalogProduct(&v, args.Name, args.Size, args.Code)
+	case *model.UpdateProductReq:
+		validateCatalogProduct(&v, args.Name, args.Size, args.Code)
+	case *model.GetProductByCodeReq:
+		validateText(&v, "code", args.Code, maxCodeLength)
+	case *model.ListProductsReq:
+		if args.Size != "" {
+			validateSize(&v, args.Size)
+		}
+		validatePagination(&v, args.Limit, args.Offset, maxProductsLimit)
+	case *model.ReceiveStockReq:
+		validateReceiveStock(&v, args)
+	case *model.TransferStockReq:
+		validateText(&v, "code", args.Code, maxCodeLength)
+		validatePositive(&v, "quantity", args.Quantity)
+		if args.FromWarehouseId == args.ToWarehouseId {
+			v.Add("to_warehouse_id", "must differ from from_warehouse_id")
+		}
+	case *model.AdjustStockReq:
+		validateAdjustStock(&v, args)
+	case *model.GetStockMovementsReq:
+		if args.From != nil && args.To != nil && !args.From.Before(*args.To) {
+			v.Add("to", "must be after from")
+		}
+		validatePagination(&v, args.Limit, args.Offset, maxStockMovementLimit)
+	}
+
+	return v.Err()
+}
+
+func validateReserveProducts(v *model.Violations, args *model.ReserveProductsReq) {
+	products := args.Products
+	if !validateBatch(v, "products", len(products)) {
+		// Lines of an oversized batch are not checked to keep the error short
+		products = nil
+	}
+
+	// The same code may be requested several times when the lines are pinned to different warehouses
+	type line struct {
+		code       string
+		warehouses string
+	}
+	lines := make(map[line]int, len(products))
+	for i, product := range products {
+		path := fmt.Sprintf("products[%d]", i)
+
+		validateCode(v, path+".code", product.Code)
+		key := line{product.Code, warehousesKey(product)}
+		if first, ok := lines[key]; ok && product.Code != "" {
+			v.Add(path+".code", "duplicates products[%d].code", first)
+		} else {
+			lines[key] = i
+		}
+
+		validatePositive(v, path+".quantity", product.Quantity)
+		if product.WarehouseId < 0 {
+			v.Add(path+".warehouse_id", "must be positive")
+		}
+		for j, id := range product.WarehouseIds {
+			validatePositive(v, fmt.Sprintf("%s.warehouse_ids[%d]", path, j), id)
+		}
+	}
+
+	switch {
+	case args.TTLSeconds < 0:
+		v.Add("ttl_seconds", "must not be negative")
+	case args.TTLSeconds > model.MaxTTLSeconds:
+		v.Add("ttl_seconds", "must be at most %d", model.MaxTTLSeconds)
+	}
+	validateIdempotencyKey(v, args.IdempotencyKey)
+	if args.Strategy != "" && !args.Strategy.IsValid() {
+		v.Add("strategy", "must be one of %s, %s, %s, %s, %s", model.StrategyLargestStockFirst, model.StrategyFewestWarehouses,
+			model.StrategyWarehousePriority, model.StrategySingleWarehouse, model.StrategyNearest)
+	}
+	for i, id := range args.WarehousePriority {
+		validatePositive(v, fmt.Sprintf("warehouse_priority[%d]", i), id)
+	}
+	if args.Destination != nil && !args.Destination.IsValid() {
+		v.Add("destination", "latitude must be from -90 to 90 and longitude from -180 to 180")
+	}
+	if utf8.RuneCountInString(args.DestinationRegion) > maxRegionLength {
+		v.Add("destination_region", "must be at most %d characters", maxRegionLength)
+	}
+}
+
+// warehousesKey identifies the warehouses a line is pinned to regardless of the order of warehouse_ids
+func warehousesKey(product model.ReserveProductReq) string {
+	ids := append([]int(nil), product.WarehouseIds...)
+	sort.Ints(ids)
+
+	return fmt.Sprint(product.WarehouseId, ids)
+}
+
+func validateReleaseProducts(v *model.Violations, args *model.ReleaseProductsReq) {
+	products := args.Products
+	if !validateBatch(v, "products", len(products)) {
+		products = nil
+	}
+
+	type line struct {
+		reservationId string
+		code          string
+	}
+	lines := make(map[line]int, len(products))
+	for i, product := range products {
+		path := fmt.Sprintf("products[%d]", i)
+
+		validateReservationId(v, path+".reservation_id", product.ReservationId)
+		validateCode(v, path+".code", product.Code)
+		key := line{product.ReservationId, product.Code}
+		if first, ok := lines[key]; ok && product.Code != "" {
+			v.Add(path+".code", "duplicates products[%d].code in the same reservation", first)
+		} else {
+			lines[key] = i
+		}
+		validatePositive(v, path+".quantity", product.Quantity)
+	}
+
+	validateIdempotencyKey(v, args.IdempotencyKey)
+}
+
+func validateRouting(v *model.Violations, region string, location *model.GeoPoint) {
+	if utf8.RuneCountInString(strings.TrimSpace(region)) > maxRegionLength {
+		v.Add("region", "must be at most %d characters", maxRegionLength)
+	}
+	if location != nil && !location.IsValid() {
+		v.Add("location", "latitude must be from -90 to 90 and longitude from -180 to 180")
+	}
+}
+
+func validateCatalogProduct(v *model.Violations, name, size, code string) {
+	validateText(v, "name", name, maxNameLength)
+	validateSize(v, size)
+	validateText(v, "code", code, maxCodeLength)
+}
+
+func validateSize(v *model.Violations, size string) {
+	if !model.IsValidProductSize(strings.ToUpper(strings.TrimSpace(size))) {
+		v.Add("size", "must be one of %s", strings.Join(model.ProductSizes, ", "))
+	}
+}
+
+// validateReceiveStock allows the same code in several lines, the service merges them
+func validateReceiveStock(v *model.Violations, args *model.ReceiveStockReq) {
+	validateText(v, "supplier_ref", args.SupplierRef, maxSupplierRefLength)
+
+	if len(args.Lines) == 0 {
+		v.Add("lines", "is required")
+	}
+	for i, line := range args.Lines {
+		path := fmt.Sprintf("lines[%d]", i)
+
+		validateText(v, path+".code", line.Code, maxCodeLength)
+		validatePositive(v, path+".quantity", line.Quantity)
+	}
+}
+
+func validateAdjustStock(v *model.Violations, args *model.AdjustStockReq) {
+	validateText(v, "code", args.Code, maxCodeLength)
+	if !args.Reason.IsValid() {
+		v.Add("reason", "must be one of %s, %s, %s, %s", model.AdjustmentDamaged, model.AdjustmentLost,
+			model.AdjustmentFound, model.AdjustmentCount)
+	}
+
+	switch {
+	case args.CountedQuantity == nil && args.Delta == nil:
+		v.Add("counted_quantity", "is required when delta is not set")
+	case args.CountedQuantity != nil && args.Delta != nil:
+		v.Add("delta", "must not be set with counted_quantity")
+	case args.CountedQuantity != nil && *args.CountedQuantity < 0:
+		v.Add("counted_quantity", "must not be negative")
+	case args.Delta != nil && *args.Delta == 0:
+		v.Add("delta", "must not be zero")
+	}
+}
+
+func validatePagination(v *model.Violations, limit, offset, maxLimit int) {
+	if limit < 0 || limit > maxLimit {
+		v.Add("limit", "must be from 0 to %d", maxLimit)
+	}
+	if offset < 0 {
+		v.Add("offset", "must not be negative")
+	}
+}
+
+func validateBatch(v *model.Violations, field string, size int) bool {
+	switch {
+	case size == 0:
+		v.Add(field, "is required")
+	case size > maxBatchSize:
+		v.Add(field, "must contain at most %d items", maxBatchSize)
+		return false
+	}
+
+	return true
+}
+
+func validateCode(v *model.Violations, field, code string) {
+	switch {
+	case code == "":
+		v.Add(field, "is required")
+	case utf8.RuneCountInString(code) > maxCodeLength:
+		v.Add(field, "must be at most %d characters", maxCodeLength)
+	case strings.TrimSpace(code) != code:
+		v.Add(field, "must not start or end with spaces")
+	case strings.IndexFunc(code, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0:
+		v.Add(field, "must contain only printable characters")
+	}
+}
+
+// validateText checks a free-form value that the service trims before use
+func validateText(v *model.Violations, field, value string, maxLength int) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		v.Add(field, "is required")
+	case utf8.RuneCountInString(value) > maxLength:
+		v.Add(field, "must be at most %d characters", maxLength)
+	}
+}
+
+func validateReservationId(v *model.Violations, field, id string) {
+	if id == "" {
+		v.Add(field, "is required")
+		return
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		v.Add(field, "must be a UUID")
+	}
+}
+
+func validatePositive(v *model.Violations, field string, value int) {
+	if value <= 0 {
+		v.Add(field, "must be positive")
+	}
+}
+
+func validateIdempotencyKey(v *model.Violations, key string) {
+	if len(key) > model.MaxIdempotencyKeyLength {
+		v.Add("idempotency_key", "must be at most %d bytes", model.MaxIdempotencyKeyLength)
+	}
+}
@@ -0,0 +1,292 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	reservationId := "8e2c1d6a-1f0b-4f52-9d38-7b2d5a0f6c11"
+	from := time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name           string
+		args           interface{}
+		wantViolations model.Violations
+	}{
+		{
+			name: "Valid reservation",
+			args: &model.ReserveProductsReq{
+				Products: []model.ReserveProductReq{
+					{Code: "12345", Quantity: 2, WarehouseIds: []int{1, 2}},
+					{Code: "123", Quantity: 1, WarehouseId: 3},
+				},
+				Strategy:    model.StrategyNearest,
+				Destination: &model.GeoPoint{Latitude: 55.75, Longitude: 37.61},
+			},
+		},
+		{
+			name: "Same code pinned to different warehouses",
+			args: &model.ReserveProductsReq{
+				Products: []model.ReserveProductReq{
+					{Code: "12345", Quantity: 1, WarehouseId: 1},
+					{Code: "12345", Quantity: 1, WarehouseId: 2},
+					{Code: "12345", Quantity: 1, WarehouseIds: []int{1, 2}},
+					{Code: "12345", Quantity: 1},
+				},
+			},
+		},
+		{
+			name: "Same code pinned to the same warehouses",
+			args: &model.ReserveProductsReq{
+				Products: []model.ReserveProductReq{
+					{Code: "12345", Quantity: 1, WarehouseIds: []int{1, 2}},
+					{Code: "12345", Quantity: 1, WarehouseIds: []int{2, 1}},
+				},
+			},
+			wantViolations: model.Violations{
+				{Field: "products[1].code", Reason: "duplicates products[0].code"},
+			},
+		},
+		{
+			name: "Reservation without products",
+			args: &model.ReserveProductsReq{},
+			wantViolations: model.Violations{
+				{Field: "products", Reason: "is required"},
+			},
+		},
+		{
+			name: "Reservation with every line invalid",
+			args: &model.ReserveProductsReq{
+				Products: []model.ReserveProductReq{
+					{Code: "12345", Quantity: 2},
+					{Code: "", Quantity: 0},
+					{Code: "12345", Quantity: -1},
+					{Code: " 12", Quantity: 1, WarehouseId: -1, WarehouseIds: []int{2, 0}},
+					{Code: strings.Repeat("1", 26), Quantity: 1},
+				},
+				TTLSeconds:        -1,
+				Strategy:          "random",
+				WarehousePriority: []int{-3},
+				Destination:       &model.GeoPoint{Latitude: 91},
+				DestinationRegion: strings.Repeat("r", 33),
+				IdempotencyKey:    strings.Repeat("k", 256),
+			},
+			wantViolations: model.Violations{
+				{Field: "products[1].code", Reason: "is required"},
+				{Field: "products[1].quantity", Reason: "must be positive"},
+				{Field: "products[2].code", Reason: "duplicates products[0].code"},
+				{Field: "products[2].quantity", Reason: "must be positive"},
+				{Field: "products[3].code", Reason: "must not start or end with spaces"},
+				{Field: "products[3].warehouse_id", Reason: "must be positive"},
+				{Field: "products[3].warehouse_ids[1]", Reason: "must be positive"},
+				{Field: "products[4].code", Reason: "must be at most 25 characters"},
+				{Field: "ttl_seconds", Reason: "must not be negative"},
+				{Field: "idempotency_key", Reason: "must be at most 255 bytes"},
+				{Field: "strategy", Reason: "must be one of largest_stock_first, fewest_warehouses, warehouse_priority, single_warehouse, nearest"},
+				{Field: "warehouse_priority[0]", Reason: "must be positive"},
+				{Field: "destination", Reason: "latitude must be from -90 to 90 and longitude from -180 to 180"},
+				{Field: "destination_region", Reason: "must be at most 32 characters"},
+			},
+		},
+		{
+			name: "Reservation with TTL out of range",
+			args: &model.ReserveProductsReq{
+				Products:   []model.ReserveProductReq{{Code: "12345", Quantity: 1}},
+				TTLSeconds: model.MaxTTLSeconds + 1,
+			},
+			wantViolations: model.Violations{
+				{Field: "ttl_seconds", Reason: "must be at most 2592000"},
+			},
+		},
+		{
+			name: "Reservation over the batch size",
+			args: &model.ReserveProductsReq{Products: make([]model.ReserveProductReq, maxBatchSize+1)},
+			wantViolations: model.Violations{
+				{Field: "products", Reason: "must contain at most 100 items"},
+			},
+		},
+		{
+			name: "Valid release",
+			args: &model.ReleaseProductsReq{Products: []model.ReleaseProductReq{
+				{ReservationId: reservationId, Code: "12345", Quantity: 1},
+				{ReservationId: reservationId, Code: "123", Quantity: 1},
+			}},
+		},
+		{
+			name: "Invalid release",
+			args: &model.ReleaseProductsReq{Products: []model.ReleaseProductReq{
+				{ReservationId: reservationId, Code: "12345", Quantity: 1},
+				{ReservationId: reservationId, Code: "12345", Quantity: 2},
+				{ReservationId: "42", Code: "1\x002", Quantity: 1},
+				{Code: "123", Quantity: 1},
+			}},
+			wantViolations: model.Violations{
+				{Field: "products[1].code", Reason: "duplicates products[0].code in the same reservation"},
+				{Field: "products[2].reservation_id", Reason: "must be a UUID"},
+				{Field: "products[2].code", Reason: "must contain only printable characters"},
+				{Field: "products[3].reservation_id", Reason: "is required"},
+			},
+		},
+		{
+			name: "Commit with invalid reservation id",
+			args: &model.CommitReservationReq{ReservationId: "not-uuid"},
+			wantViolations: model.Violations{
+				{Field: "reservation_id", Reason: "must be a UUID"},
+			},
+		},
+		{
+			name: "Cancel without reservation id",
+			args: &model.CancelReservationReq{},
+			wantViolations: model.Violations{
+				{Field: "reservation_id", Reason: "is required"},
+			},
+		},
+		{
+			name: "Valid reservation lookup",
+			args: &model.GetReservationReq{ReservationId: reservationId},
+		},
+		{
+			name: "Products by invalid warehouse",
+			args: &model.ShowProductsReq{WarehouseId: 0},
+			wantViolations: model.Violations{
+				{Field: "warehouse_id", Reason: "must be positive"},
+			},
+		},
+		{
+			name: "Valid warehouse",
+			args: &model.CreateWarehouseReq{Name: " Podolsk ", Region: "moscow", Location: &model.GeoPoint{Latitude: 55.43, Longitude: 37.55}},
+		},
+		{
+			name: "Invalid warehouse",
+			args: &model.CreateWarehouseReq{Name: "  ", Region: strings.Repeat("r", 33), Location: &model.GeoPoint{Latitude: 91}},
+			wantViolations: model.Violations{
+				{Field: "name", Reason: "is required"},
+				{Field: "region", Reason: "must be at most 32 characters"},
+				{Field: "location", Reason: "latitude must be from -90 to 90 and longitude from -180 to 180"},
+			},
+		},
+		{
+			name: "Warehouse name too long",
+			args: &model.UpdateWarehouseReq{WarehouseId: 1, Name: strings.Repeat("n", 26)},
+			wantViolations: model.Violations{
+				{Field: "name", Reason: "must be at most 25 characters"},
+			},
+		},
+		{
+			name: "Valid catalog product",
+			args: &model.CreateProductReq{Name: "Puma Cap", Size: " m ", Code: "777"},
+		},
+		{
+			name: "Invalid catalog product",
+			args: &model.UpdateProductReq{ID: 1, Name: "", Size: "XXXXL", Code: strings.Repeat("1", 26)},
+			wantViolations: model.Violations{
+				{Field: "name", Reason: "is required"},
+				{Field: "size", Reason: "must be one of XS, S, M, L, XL, XXL, XXXL"},
+				{Field: "code", Reason: "must be at most 25 characters"},
+			},
+		},
+		{
+			name: "Invalid catalog page",
+			args: &model.ListProductsReq{Size: "XXS", Limit: 101, Offset: -1},
+			wantViolations: model.Violations{
+				{Field: "size", Reason: "must be one of XS, S, M, L, XL, XXL, XXXL"},
+				{Field: "limit", Reason: "must be from 0 to 100"},
+				{Field: "offset", Reason: "must not be negative"},
+			},
+		},
+		{
+			name: "Valid receipt with repeated code",
+			args: &model.ReceiveStockReq{
+				SupplierRef: "INV-2024-001",
+				WarehouseId: 1,
+				Lines:       []model.ReceiveStockLineReq{{Code: "12345", Quantity: 1}, {Code: "12345", Quantity: 2}},
+			},
+		},
+		{
+			name: "Invalid receipt",
+			args: &model.ReceiveStockReq{
+				SupplierRef: strings.Repeat("s", 65),
+				Lines:       []model.ReceiveStockLineReq{{Code: " ", Quantity: 0}},
+			},
+			wantViolations: model.Violations{
+				{Field: "supplier_ref", Reason: "must be at most 64 characters"},
+				{Field: "lines[0].code", Reason: "is required"},
+				{Field: "lines[0].quantity", Reason: "must be positive"},
+			},
+		},
+		{
+			name: "Receipt without lines",
+			args: &model.ReceiveStockReq{SupplierRef: "INV-2024-001"},
+			wantViolations: model.Violations{
+				{Field: "lines", Reason: "is required"},
+			},
+		},
+		{
+			name: "Transfer to the same warehouse",
+			args: &model.TransferStockReq{Code: "12345", FromWarehouseId: 1, ToWarehouseId: 1},
+			wantViolations: model.Violations{
+				{Field: "quantity", Reason: "must be positive"},
+				{Field: "to_warehouse_id", Reason: "must differ from from_warehouse_id"},
+			},
+		},
+		{
+			name: "Adjustment with count and delta",
+			args: &model.AdjustStockReq{Code: "12345", CountedQuantity: new(int), Delta: new(int), Reason: "stolen"},
+			wantViolations: model.Violations{
+				{Field: "reason", Reason: "must be one of damaged, lost, found, count"},
+				{Field: "delta", Reason: "must not be set with counted_quantity"},
+			},
+		},
+		{
+			name: "Adjustment without count and delta",
+			args: &model.AdjustStockReq{Code: "12345", Reason: model.AdjustmentCount},
+			wantViolations: model.Violations{
+				{Field: "counted_quantity", Reason: "is required when delta is not set"},
+			},
+		},
+		{
+			name: "Adjustment by zero",
+			args: &model.AdjustStockReq{Code: "12345", Delta: new(int), Reason: model.AdjustmentFound},
+			wantViolations: model.Violations{
+				{Field: "delta", Reason: "must not be zero"},
+			},
+		},
+		{
+			name: "Movements with inverted time range",
+			args: &model.GetStockMovementsReq{From: &to, To: &from, Limit: 1001},
+			wantViolations: model.Violations{
+				{Field: "to", Reason: "must be after from"},
+				{Field: "limit", Reason: "must be from 0 to 1000"},
+			},
+		},
+		{
+			name: "Request without rules",
+			args: &model.ListWarehousesReq{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.args)
+			if tt.wantViolations == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, model.ErrValidation)
+			assert.Equal(t, model.CodeInvalidInput, model.CodeOf(err))
+
+			var modelErr *model.Error
+			if assert.True(t, errors.As(err, &modelErr)) {
+				assert.Equal(t, tt.wantViolations, modelErr.Details["violations"])
+			}
+		})
+	}
+}
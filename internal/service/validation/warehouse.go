@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"net/http"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+// WarehouseService validates requests before they reach the wrapped service
+type WarehouseService struct {
+	next service.WarehouseService
+}
+
+func NewWarehouseService(next service.WarehouseService) *WarehouseService {
+	return &WarehouseService{next: next}
+}
+
+func (s *WarehouseService) CreateWarehouse(r *http.Request, args *model.CreateWarehouseReq, reply *model.Warehouse) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.CreateWarehouse(r, args, reply)
+}
+
+func (s *WarehouseService) UpdateWarehouse(r *http.Request, args *model.UpdateWarehouseReq, reply *model.Warehouse) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.UpdateWarehouse(r, args, reply)
+}
+
+func (s *WarehouseService) SetWarehouseAvailability(r *http.Request, args *model.SetWarehouseAvailabilityReq, reply *model.Warehouse) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.SetWarehouseAvailability(r, args, reply)
+}
+
+func (s *WarehouseService) SetWarehouseRouting(r *http.Request, args *model.SetWarehouseRoutingReq, reply *model.Warehouse) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.SetWarehouseRouting(r, args, reply)
+}
+
+func (s *WarehouseService) ListWarehouses(r *http.Request, args *model.ListWarehousesReq, reply *[]model.Warehouse) error {
+	if err := Validate(args); err != nil {
+		return err
+	}
+
+	return s.next.ListWarehouses(r, args, reply)
+}
@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type warehouseServiceStub struct {
+	service.WarehouseService
+	calls int
+}
+
+func (s *warehouseServiceStub) CreateWarehouse(_ *http.Request, args *model.CreateWarehouseReq, reply *model.Warehouse) error {
+	s.calls++
+	*reply = model.Warehouse{ID: 1, Name: args.Name}
+	return nil
+}
+
+func TestWarehouseService(t *testing.T) {
+	tests := []struct {
+		name      string
+		whName    string
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "Valid request reaches the service",
+			whName:    "Podolsk",
+			wantCalls: 1,
+		},
+		{
+			name:    "Invalid request is rejected",
+			whName:  " ",
+			wantErr: model.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &warehouseServiceStub{}
+			s := NewWarehouseService(next)
+			reply := new(model.Warehouse)
+
+			err := s.CreateWarehouse(httptest.NewRequest(http.MethodPost, "/rpc", nil), &model.CreateWarehouseReq{Name: tt.whName}, reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.whName, reply.Name)
+			}
+			assert.Equal(t, tt.wantCalls, next.calls)
+		})
+	}
+}
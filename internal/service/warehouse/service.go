@@ -0,0 +1,118 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/dbutil"
+	"github.com/pintoter/warehouse-api/internal/repository"
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/pintoter/warehouse-api/pkg/logger"
+)
+
+type Service struct {
+	repo      repository.WarehousesRepository
+	txManager dbutil.TxManager
+}
+
+func NewService(repo repository.WarehousesRepository, txManager dbutil.TxManager) service.WarehouseService {
+	return &Service{
+		repo:      repo,
+		txManager: txManager,
+	}
+}
+
+func (s *Service) CreateWarehouse(r *http.Request, args *model.CreateWarehouseReq, reply *model.Warehouse) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	wh, err := s.repo.CreateWarehouse(ctx, model.Warehouse{
+		Name:         strings.TrimSpace(args.Name),
+		Availability: args.Availability,
+		Priority:     args.Priority,
+		Region:       strings.TrimSpace(args.Region),
+		Location:     args.Location,
+	})
+	if err != nil {
+		return warehouseErr(ctx, err)
+	}
+
+	*reply = wh
+	return nil
+}
+
+func (s *Service) UpdateWarehouse(r *http.Request, args *model.UpdateWarehouseReq, reply *model.Warehouse) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	wh, err := s.repo.UpdateWarehouseName(ctx, args.WarehouseId, strings.TrimSpace(args.Name))
+	if err != nil {
+		return warehouseErr(ctx, err)
+	}
+
+	*reply = wh
+	return nil
+}
+
+func (s *Service) SetWarehouseAvailability(r *http.Request, args *model.SetWarehouseAvailabilityReq, reply *model.Warehouse) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	wh, err := s.repo.UpdateWarehouseAvailability(ctx, args.WarehouseId, args.Availability)
+	if err != nil {
+		return warehouseErr(ctx, err)
+	}
+
+	*reply = wh
+	return nil
+}
+
+// SetWarehouseRouting sets the priority, region and location used by reservation allocation strategies
+func (s *Service) SetWarehouseRouting(r *http.Request, args *model.SetWarehouseRoutingReq, reply *model.Warehouse) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	wh, err := s.repo.UpdateWarehouseRouting(ctx, args.WarehouseId, args.Priority, strings.TrimSpace(args.Region), args.Location)
+	if err != nil {
+		return warehouseErr(ctx, err)
+	}
+
+	*reply = wh
+	return nil
+}
+
+func (s *Service) ListWarehouses(r *http.Request, _ *model.ListWarehousesReq, reply *[]model.Warehouse) error {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var warehouses []model.Warehouse
+	err := s.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		warehouses, err = s.repo.GetWarehouses(ctx)
+		return err
+	}, dbutil.WithIsolation(sql.LevelReadCommitted), dbutil.WithReadOnly())
+	if err != nil {
+		return warehouseErr(ctx, err)
+	}
+
+	*reply = warehouses
+	return nil
+}
+
+// warehouseErr maps a repository error to a model error
+func warehouseErr(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return model.ErrWarehouseNotFound
+	case dbutil.IsUniqueViolation(err):
+		return model.ErrWarehouseAlreadyExists
+	default:
+		logger.DebugKV(ctx, "Warehouse", "err", err)
+		return model.ErrInternalServer
+	}
+}
@@ -0,0 +1,228 @@
+package warehouse
+
+import (
+	"database/sql"
+	"log"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/pintoter/warehouse-api/internal/dbutil/transaction"
+	productRepository "github.com/pintoter/warehouse-api/internal/repository/product"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type txConfig struct{}
+
+func (txConfig) GetMaxRetries() int {
+	return 0
+}
+
+func (txConfig) GetRetryBaseDelay() time.Duration {
+	return 0
+}
+
+func (txConfig) GetRetryMaxDelay() time.Duration {
+	return 0
+}
+
+func TestCreateWarehouse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func(args *model.CreateWarehouseReq)
+
+	expectedQuery := "INSERT INTO warehouse (name,availability,priority,region,latitude,longitude) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id, name, availability, priority, region, latitude, longitude"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.CreateWarehouseReq
+		wantReply    model.Warehouse
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args *model.CreateWarehouseReq) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs("Podolsk", args.Availability, 0, "", nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "availability", "priority", "region", "latitude", "longitude"}).AddRow(4, "Podolsk", true, 0, "", nil, nil))
+			},
+			args:      &model.CreateWarehouseReq{Name: "  Podolsk ", Availability: true},
+			wantReply: model.Warehouse{ID: 4, Name: "Podolsk", Availability: true},
+		},
+		{
+			name: "Duplicate name",
+			mockBehavior: func(args *model.CreateWarehouseReq) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.Name, args.Availability, 0, "", nil, nil).
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+			},
+			args:    &model.CreateWarehouseReq{Name: "Domodedovo"},
+			wantErr: model.ErrWarehouseAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			var reply model.Warehouse
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.CreateWarehouse(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSetWarehouseAvailability(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func(args *model.SetWarehouseAvailabilityReq)
+
+	expectedQuery := "UPDATE warehouse SET availability = $1 WHERE id = $2 RETURNING id, name, availability, priority, region, latitude, longitude"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.SetWarehouseAvailabilityReq
+		wantReply    model.Warehouse
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args *model.SetWarehouseAvailabilityReq) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.Availability, args.WarehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "availability", "priority", "region", "latitude", "longitude"}).AddRow(3, "Molchanovo", true, 0, "", nil, nil))
+			},
+			args:      &model.SetWarehouseAvailabilityReq{WarehouseId: 3, Availability: true},
+			wantReply: model.Warehouse{ID: 3, Name: "Molchanovo", Availability: true},
+		},
+		{
+			name: "Not found",
+			mockBehavior: func(args *model.SetWarehouseAvailabilityReq) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.Availability, args.WarehouseId).
+					WillReturnError(sql.ErrNoRows)
+			},
+			args:    &model.SetWarehouseAvailabilityReq{WarehouseId: 100},
+			wantErr: model.ErrWarehouseNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			var reply model.Warehouse
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.SetWarehouseAvailability(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSetWarehouseRouting(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	s := NewService(productRepository.NewRepository(sqlxDB), transaction.NewTransactionManager(sqlxDB, txConfig{}))
+
+	type mockBehavior func(args *model.SetWarehouseRoutingReq)
+
+	expectedQuery := "UPDATE warehouse SET latitude = $1, longitude = $2, priority = $3, region = $4 WHERE id = $5 RETURNING id, name, availability, priority, region, latitude, longitude"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         *model.SetWarehouseRoutingReq
+		wantReply    model.Warehouse
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args *model.SetWarehouseRoutingReq) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(55.41, 37.9, args.Priority, "moscow", args.WarehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "availability", "priority", "region", "latitude", "longitude"}).AddRow(1, "Domodedovo", true, 5, "moscow", 55.41, 37.9))
+			},
+			args:      &model.SetWarehouseRoutingReq{WarehouseId: 1, Priority: 5, Region: " moscow ", Location: &model.GeoPoint{Latitude: 55.41, Longitude: 37.9}},
+			wantReply: model.Warehouse{ID: 1, Name: "Domodedovo", Availability: true, Priority: 5, Region: "moscow", Location: &model.GeoPoint{Latitude: 55.41, Longitude: 37.9}},
+		},
+		{
+			name: "Clear location",
+			mockBehavior: func(args *model.SetWarehouseRoutingReq) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(nil, nil, 0, "", args.WarehouseId).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "availability", "priority", "region", "latitude", "longitude"}).AddRow(2, "Sharikovo", true, 0, "", nil, nil))
+			},
+			args:      &model.SetWarehouseRoutingReq{WarehouseId: 2},
+			wantReply: model.Warehouse{ID: 2, Name: "Sharikovo", Availability: true},
+		},
+		{
+			name: "Not found",
+			mockBehavior: func(args *model.SetWarehouseRoutingReq) {
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(nil, nil, 0, "", args.WarehouseId).
+					WillReturnError(sql.ErrNoRows)
+			},
+			args:    &model.SetWarehouseRoutingReq{WarehouseId: 100},
+			wantErr: model.ErrWarehouseNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			var reply model.Warehouse
+			req := httptest.NewRequest("POST", "/rpc", nil)
+			err := s.SetWarehouseRouting(req, tt.args, &reply)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReply, reply)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
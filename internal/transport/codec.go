@@ -0,0 +1,158 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+// JSON-RPC error codes: the reserved ones from the specification and the application ones
+const (
+	codeParseError        = -32700
+	codeInvalidRequest    = -32600
+	codeMethodNotFound    = -32601
+	codeInvalidParams     = -32602
+	codeInternalError     = -32603
+	codeNotFound          = -32001
+	codeInsufficientStock = -32002
+	codeConflict          = -32003
+)
+
+var rpcCodes = map[model.ErrorCode]int{
+	model.CodeInvalidInput:      codeInvalidParams,
+	model.CodeNotFound:          codeNotFound,
+	model.CodeInsufficientStock: codeInsufficientStock,
+	model.CodeConflict:          codeConflict,
+	model.CodeInternal:          codeInternalError,
+}
+
+var null = json.RawMessage("null")
+
+type jsonRequest struct {
+	Method string           `json:"method"`
+	Params *json.RawMessage `json:"params"`
+	Id     *json.RawMessage `json:"id"`
+}
+
+type jsonResponse struct {
+	Result interface{}      `json:"result"`
+	Error  interface{}      `json:"error"`
+	Id     *json.RawMessage `json:"id"`
+}
+
+// jsonError is the JSON-RPC error object, data holds the structured model error
+type jsonError struct {
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Data    *model.Error `json:"data,omitempty"`
+}
+
+// codecError is a failure to decode the request itself
+type codecError struct {
+	code int
+	err  error
+}
+
+func (e *codecError) Error() string {
+	return e.err.Error()
+}
+
+// jsonCodec reads JSON-RPC 1.0 requests like gorilla's json codec,
+// but reports errors as objects with distinct codes instead of plain strings
+type jsonCodec struct{}
+
+func newJSONCodec() *jsonCodec {
+	return &jsonCodec{}
+}
+
+func (c *jsonCodec) NewRequest(r *http.Request) rpc.CodecRequest {
+	req := new(jsonRequest)
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return &jsonCodecRequest{request: req, err: &codecError{code: codeParseError, err: err}}
+	}
+
+	return &jsonCodecRequest{request: req}
+}
+
+type jsonCodecRequest struct {
+	request *jsonRequest
+	err     error
+}
+
+func (c *jsonCodecRequest) Method() (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+
+	if alias, ok := rpcMethodAliases[c.request.Method]; ok {
+		return alias, nil
+	}
+	return c.request.Method, nil
+}
+
+func (c *jsonCodecRequest) ReadRequest(args interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	if c.request.Params == nil {
+		c.err = &codecError{code: codeInvalidParams, err: errors.New("rpc: method request ill-formed: missing params field")}
+		return c.err
+	}
+
+	// JSON params is array value, RPC params is struct
+	params := [1]interface{}{args}
+	if err := json.Unmarshal(*c.request.Params, &params); err != nil {
+		c.err = &codecError{code: codeInvalidParams, err: err}
+	}
+
+	return c.err
+}
+
+func (c *jsonCodecRequest) WriteResponse(w http.ResponseWriter, reply interface{}) {
+	c.write(w, http.StatusOK, &jsonResponse{Result: reply, Error: &null, Id: c.request.Id})
+}
+
+func (c *jsonCodecRequest) WriteError(w http.ResponseWriter, status int, err error) {
+	rpcErr, status := newJSONError(err, status)
+
+	id := c.request.Id
+	if id == nil && rpcErr.Data == nil {
+		// The request could not be read, so the client gets the error even without an id
+		id = &null
+	}
+	c.write(w, status, &jsonResponse{Result: &null, Error: rpcErr, Id: id})
+}
+
+// newJSONError converts err to the JSON-RPC error object and the HTTP status of the response
+func newJSONError(err error, status int) (*jsonError, int) {
+	var codecErr *codecError
+
+	switch {
+	case errors.As(err, &codecErr):
+		return &jsonError{Code: codecErr.code, Message: codecErr.Error()}, status
+	case strings.HasPrefix(err.Error(), "rpc: "):
+		// gorilla reports unknown services and methods with the "rpc: " prefix
+		return &jsonError{Code: codeMethodNotFound, Message: err.Error()}, status
+	default:
+		// The method was called, the failure is reported in the body like a regular response
+		modelErr := model.NewError(err)
+		return &jsonError{Code: rpcCodes[modelErr.Code], Message: modelErr.Message, Data: modelErr}, http.StatusOK
+	}
+}
+
+func (c *jsonCodecRequest) write(w http.ResponseWriter, status int, res *jsonResponse) {
+	if res.Id == nil {
+		// Notifications don't have a response
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
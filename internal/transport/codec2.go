@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/rpc/v2"
+)
+
+const jsonRPCVersion = "2.0"
+
+type json2Request struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	// Id is empty for notifications and "null" for requests with the null id
+	Id json.RawMessage `json:"id"`
+}
+
+type json2Response struct {
+	Version string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *jsonError      `json:"error,omitempty"`
+	Id      json.RawMessage `json:"id"`
+}
+
+// json2Codec reads JSON-RPC 2.0 requests: params may be an object or an array
+// with a single object, requests without an id are notifications
+type json2Codec struct{}
+
+func newJSON2Codec() *json2Codec {
+	return &json2Codec{}
+}
+
+func (c *json2Codec) NewRequest(r *http.Request) rpc.CodecRequest {
+	req := new(json2Request)
+	err := json.NewDecoder(r.Body).Decode(req)
+
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr):
+		err = &codecError{code: codeInvalidRequest, err: err}
+	case err != nil:
+		err = &codecError{code: codeParseError, err: err}
+	case req.Version != jsonRPCVersion:
+		err = &codecError{code: codeInvalidRequest, err: errors.New(`rpc: jsonrpc must be "2.0"`)}
+	case req.Method == "":
+		err = &codecError{code: codeInvalidRequest, err: errors.New("rpc: method is required")}
+	}
+
+	if err != nil && (typeErr != nil || len(req.Id) == 0) {
+		// A malformed request gets the error even without an id, the specification requires null then
+		req.Id = null
+	}
+
+	return &json2CodecRequest{request: req, err: err}
+}
+
+type json2CodecRequest struct {
+	request *json2Request
+	err     error
+}
+
+func (c *json2CodecRequest) Method() (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+
+	if alias, ok := rpcMethodAliases[c.request.Method]; ok {
+		return alias, nil
+	}
+	return c.request.Method, nil
+}
+
+func (c *json2CodecRequest) ReadRequest(args interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	params := bytes.TrimSpace(c.request.Params)
+	if len(params) == 0 || bytes.Equal(params, null) {
+		// Params may be omitted, the method gets zero args
+		return nil
+	}
+
+	var err error
+	if params[0] == '[' {
+		err = json.Unmarshal(params, &[1]interface{}{args})
+	} else {
+		err = json.Unmarshal(params, args)
+	}
+	if err != nil {
+		c.err = &codecError{code: codeInvalidParams, err: err}
+	}
+
+	return c.err
+}
+
+func (c *json2CodecRequest) WriteResponse(w http.ResponseWriter, reply interface{}) {
+	c.write(w, http.StatusOK, &json2Response{Version: jsonRPCVersion, Result: reply, Id: c.request.Id})
+}
+
+func (c *json2CodecRequest) WriteError(w http.ResponseWriter, status int, err error) {
+	rpcErr, status := newJSONError(err, status)
+	c.write(w, status, &json2Response{Version: jsonRPCVersion, Error: rpcErr, Id: c.request.Id})
+}
+
+func (c *json2CodecRequest) write(w http.ResponseWriter, status int, res *json2Response) {
+	if len(res.Id) == 0 {
+		// Notifications don't have a response
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
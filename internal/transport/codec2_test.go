@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON2Codec(t *testing.T) {
+	server := rpc.NewServer()
+	server.RegisterCodec(newJSON2Codec(), "application/json-rpc")
+	err := server.RegisterService(EchoService{}, "Echo")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Params by name",
+			body:       `{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"hi"},"id":1}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"jsonrpc":"2.0","result":{"value":"hi"},"id":1}`,
+		},
+		{
+			name:       "Params in array",
+			body:       `{"jsonrpc":"2.0","method":"Echo.Echo","params":[{"value":"hi"}],"id":"a"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"jsonrpc":"2.0","result":{"value":"hi"},"id":"a"}`,
+		},
+		{
+			name:       "Without params",
+			body:       `{"jsonrpc":"2.0","method":"Echo.Echo","id":1}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"jsonrpc":"2.0","result":{"value":""},"id":1}`,
+		},
+		{
+			name:       "Null id",
+			body:       `{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"hi"},"id":null}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"jsonrpc":"2.0","result":{"value":"hi"},"id":null}`,
+		},
+		{
+			name:       "Not found",
+			body:       `{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"missing"},"id":1}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"jsonrpc":"2.0","error":{"code":-32001,"message":"product not found","data":{"code":"NOT_FOUND","message":"product not found"}},"id":1}`,
+		},
+		{
+			name:       "Notification",
+			body:       `{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"missing"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   ``,
+		},
+		{
+			name:       "Unknown method",
+			body:       `{"jsonrpc":"2.0","method":"Echo.Shout","id":1}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"jsonrpc":"2.0","error":{"code":-32601,"message":"rpc: can't find method \"Echo.Shout\""},"id":1}`,
+		},
+		{
+			name:       "Invalid params",
+			body:       `{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":1},"id":1}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"jsonrpc":"2.0","error":{"code":-32602,"message":"json: cannot unmarshal number into Go struct field EchoReq.value of type string"},"id":1}`,
+		},
+		{
+			name:       "Wrong version",
+			body:       `{"jsonrpc":"1.0","method":"Echo.Echo","id":1}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"jsonrpc":"2.0","error":{"code":-32600,"message":"rpc: jsonrpc must be \"2.0\""},"id":1}`,
+		},
+		{
+			name:       "Invalid request",
+			body:       `{"jsonrpc":"2.0","method":1,"id":1}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"jsonrpc":"2.0","error":{"code":-32600,"message":"json: cannot unmarshal number into Go struct field json2Request.method of type string"},"id":null}`,
+		},
+		{
+			name:       "Parse error",
+			body:       `{"jsonrpc":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"jsonrpc":"2.0","error":{"code":-32700,"message":"unexpected EOF"},"id":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json-rpc")
+			rec := httptest.NewRecorder()
+
+			server.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantBody == "" {
+				assert.Empty(t, rec.Body.String())
+			} else {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
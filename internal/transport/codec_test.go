@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type EchoReq struct {
+	Value string `json:"value"`
+}
+
+type EchoService struct{}
+
+func (EchoService) Echo(_ *http.Request, args *EchoReq, reply *EchoReq) error {
+	switch args.Value {
+	case "missing":
+		return model.ErrProductNotFound
+	case "short":
+		return model.WithDetails(model.ErrInvalidQuantity, map[string]interface{}{"available": 2})
+	case "broken":
+		return model.ErrInternalServer
+	}
+
+	*reply = *args
+	return nil
+}
+
+func TestJSONCodec(t *testing.T) {
+	server := rpc.NewServer()
+	server.RegisterCodec(newJSONCodec(), "application/json")
+	err := server.RegisterService(EchoService{}, "Echo")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Success",
+			body:       `{"method":"Echo.Echo","params":[{"value":"hi"}],"id":1}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"result":{"value":"hi"},"error":null,"id":1}`,
+		},
+		{
+			name:       "Not found",
+			body:       `{"method":"Echo.Echo","params":[{"value":"missing"}],"id":1}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"result":null,"error":{"code":-32001,"message":"product not found","data":{"code":"NOT_FOUND","message":"product not found"}},"id":1}`,
+		},
+		{
+			name:       "Insufficient stock with details",
+			body:       `{"method":"Echo.Echo","params":[{"value":"short"}],"id":"a"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"result":null,"error":{"code":-32002,"message":"required quantity of products is missing","data":{"code":"INSUFFICIENT_STOCK","message":"required quantity of products is missing","details":{"available":2}}},"id":"a"}`,
+		},
+		{
+			name:       "Internal",
+			body:       `{"method":"Echo.Echo","params":[{"value":"broken"}],"id":1}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"result":null,"error":{"code":-32603,"message":"internal server error, try later","data":{"code":"INTERNAL","message":"internal server error, try later"}},"id":1}`,
+		},
+		{
+			name:       "Unknown method",
+			body:       `{"method":"Echo.Shout","params":[{}],"id":1}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"result":null,"error":{"code":-32601,"message":"rpc: can't find method \"Echo.Shout\""},"id":1}`,
+		},
+		{
+			name:       "Missing params",
+			body:       `{"method":"Echo.Echo","id":1}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"result":null,"error":{"code":-32602,"message":"rpc: method request ill-formed: missing params field"},"id":1}`,
+		},
+		{
+			name:       "Parse error",
+			body:       `{"method":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"result":null,"error":{"code":-32700,"message":"unexpected EOF"},"id":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			server.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
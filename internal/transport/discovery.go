@@ -0,0 +1,230 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+)
+
+const apiTitle = "Warehouse API"
+
+// apiVersion is the version of the published documents, bump it on changes of the contract
+const apiVersion = "1.0.0"
+
+// rpcMethodAliases maps method names of the JSON-RPC specification to the gorilla service methods
+var rpcMethodAliases = map[string]string{
+	"rpc.discover": "rpc.Discover",
+}
+
+// rpcServices are the services registered on /rpc with their interfaces
+var rpcServices = []struct {
+	name  string
+	iface reflect.Type
+}{
+	{"ProductService", reflect.TypeOf((*service.ProductService)(nil)).Elem()},
+	{"WarehouseService", reflect.TypeOf((*service.WarehouseService)(nil)).Elem()},
+	{"CatalogService", reflect.TypeOf((*service.CatalogService)(nil)).Elem()},
+	{"InventoryService", reflect.TypeOf((*service.InventoryService)(nil)).Elem()},
+}
+
+type docInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openRPCDoc struct {
+	OpenRPC    string          `json:"openrpc"`
+	Info       docInfo         `json:"info"`
+	Servers    []openRPCServer `json:"servers"`
+	Methods    []openRPCMethod `json:"methods"`
+	Components docComponents   `json:"components"`
+}
+
+type openRPCServer struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type openRPCMethod struct {
+	Name           string                     `json:"name"`
+	ParamStructure string                     `json:"paramStructure"`
+	Params         []openRPCContentDescriptor `json:"params"`
+	Result         openRPCContentDescriptor   `json:"result"`
+}
+
+type openRPCContentDescriptor struct {
+	Name     string     `json:"name"`
+	Required bool       `json:"required,omitempty"`
+	Schema   jsonSchema `json:"schema"`
+}
+
+type docComponents struct {
+	Schemas map[string]jsonSchema `json:"schemas"`
+}
+
+type openAPIDoc struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       docInfo                                `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components docComponents                          `json:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIBody               `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string     `json:"name"`
+	In       string     `json:"in"`
+	Required bool       `json:"required"`
+	Schema   jsonSchema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema jsonSchema `json:"schema"`
+}
+
+// newOpenRPCDoc describes the methods of rpcServices, params are passed by position in a one element array
+func newOpenRPCDoc() *openRPCDoc {
+	schemas := newSchemaRegistry()
+	doc := &openRPCDoc{
+		OpenRPC: "1.2.6",
+		Info:    docInfo{Title: apiTitle, Version: apiVersion},
+		Servers: []openRPCServer{{Name: "rpc", URL: "/rpc"}},
+		Methods: []openRPCMethod{},
+	}
+
+	for _, svc := range rpcServices {
+		for i := 0; i < svc.iface.NumMethod(); i++ {
+			method := svc.iface.Method(i)
+			doc.Methods = append(doc.Methods, openRPCMethod{
+				Name:           svc.name + "." + method.Name,
+				ParamStructure: "by-position",
+				Params: []openRPCContentDescriptor{
+					{Name: "args", Required: true, Schema: schemas.schemaOf(method.Type.In(1))},
+				},
+				Result: openRPCContentDescriptor{Name: "reply", Schema: schemas.schemaOf(method.Type.In(2))},
+			})
+		}
+	}
+
+	doc.Components.Schemas = schemas.schemas
+	return doc
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)(:[^}]*)?\}`)
+
+// newOpenAPIDoc describes restRoutes, path parameters with a numeric pattern are integers
+func newOpenAPIDoc() *openAPIDoc {
+	schemas := newSchemaRegistry()
+	errorSchema := schemas.schemaOf(reflect.TypeOf(restError{}))
+	doc := &openAPIDoc{
+		OpenAPI: "3.0.3",
+		Info:    docInfo{Title: apiTitle, Version: apiVersion},
+		Paths:   make(map[string]map[string]openAPIOperation),
+	}
+
+	for _, route := range restRoutes {
+		replySchema := schemas.schemaOf(reflect.TypeOf(route.reply))
+		operation := openAPIOperation{
+			OperationID: route.operation,
+			Responses: map[string]openAPIResponse{
+				strconv.Itoa(route.status): {
+					Description: http.StatusText(route.status),
+					Content:     jsonContent(replySchema),
+				},
+				"default": {Description: "Error", Content: jsonContent(errorSchema)},
+			},
+		}
+
+		if route.lineResults {
+			// An error status carries either the error of the request or the reply with every line rejected
+			for _, status := range errorStatuses() {
+				operation.Responses[strconv.Itoa(status)] = openAPIResponse{
+					Description: http.StatusText(status),
+					Content:     jsonContent(jsonSchema{"oneOf": []jsonSchema{replySchema, errorSchema}}),
+				}
+			}
+		}
+
+		for _, match := range pathParamPattern.FindAllStringSubmatch(route.path, -1) {
+			schema := jsonSchema{"type": "string"}
+			if match[2] == ":[0-9]+" {
+				schema = jsonSchema{"type": "integer"}
+			}
+			operation.Parameters = append(operation.Parameters, openAPIParameter{Name: match[1], In: "path", Required: true, Schema: schema})
+		}
+
+		if route.request != nil {
+			operation.RequestBody = &openAPIBody{Required: true, Content: jsonContent(schemas.schemaOf(reflect.TypeOf(route.request)))}
+		}
+
+		path := pathParamPattern.ReplaceAllString(route.path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]openAPIOperation)
+		}
+		doc.Paths[path][strings.ToLower(route.method)] = operation
+	}
+
+	doc.Components.Schemas = schemas.schemas
+	return doc
+}
+
+// errorStatuses returns the sorted statuses of httpStatuses
+func errorStatuses() []int {
+	statuses := make([]int, 0, len(httpStatuses))
+	for _, status := range httpStatuses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	return statuses
+}
+
+func jsonContent(schema jsonSchema) map[string]openAPIMediaType {
+	return map[string]openAPIMediaType{"application/json": {Schema: schema}}
+}
+
+// DiscoveryService serves rpc.discover with the OpenRPC document
+type DiscoveryService struct {
+	doc json.RawMessage
+}
+
+func newDiscoveryService() *DiscoveryService {
+	doc, _ := json.Marshal(newOpenRPCDoc())
+	return &DiscoveryService{doc: doc}
+}
+
+func (s *DiscoveryService) Discover(_ *http.Request, _ *struct{}, reply *json.RawMessage) error {
+	*reply = s.doc
+	return nil
+}
+
+// openAPIHandler serves GET /openapi.json
+func openAPIHandler() http.HandlerFunc {
+	doc, _ := json.Marshal(newOpenAPIDoc())
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write(doc)
+	}
+}
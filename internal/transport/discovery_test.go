@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	server := rpc.NewServer()
+	err := server.RegisterService(newDiscoveryService(), "rpc")
+	assert.NoError(t, err)
+
+	handler := newRPCHandler(server)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "JSON-RPC 1.0",
+			body: `{"method":"rpc.discover","params":[],"id":1}`,
+		},
+		{
+			name: "JSON-RPC 2.0",
+			body: `{"jsonrpc":"2.0","method":"rpc.discover","id":1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			var res struct {
+				Result openRPCDoc `json:"result"`
+			}
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Equal(t, "1.2.6", res.Result.OpenRPC)
+			assert.Len(t, res.Result.Methods, 20)
+		})
+	}
+}
+
+func TestOpenRPCDoc(t *testing.T) {
+	doc := newOpenRPCDoc()
+
+	var reserve *openRPCMethod
+	for i := range doc.Methods {
+		if doc.Methods[i].Name == "ProductService.ReserveProducts" {
+			reserve = &doc.Methods[i]
+		}
+	}
+
+	if assert.NotNil(t, reserve) {
+		assert.Equal(t, jsonSchema{"$ref": "#/components/schemas/ReserveProductsReq"}, reserve.Params[0].Schema)
+		assert.Equal(t, jsonSchema{"$ref": "#/components/schemas/ReserveProductsResp"}, reserve.Result.Schema)
+	}
+
+	assert.Equal(t, jsonSchema{
+		"type": "object",
+		"properties": map[string]jsonSchema{
+			"code":         {"type": "string"},
+			"quantity":     {"type": "integer"},
+			"warehouse_id": {"type": "integer"},
+			"warehouse_ids": {
+				"type":  "array",
+				"items": jsonSchema{"type": "integer"},
+			},
+		},
+	}, doc.Components.Schemas["ReserveProductReq"])
+	assert.Equal(t, jsonSchema{"type": "string", "format": "date-time"},
+		doc.Components.Schemas["ReservationLine"]["properties"].(map[string]jsonSchema)["reserved_at"])
+	assert.Equal(t, jsonSchema{"$ref": "#/components/schemas/GeoPoint"},
+		doc.Components.Schemas["ReserveProductsReq"]["properties"].(map[string]jsonSchema)["destination"])
+}
+
+func TestOpenAPIDoc(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+
+	openAPIHandler().ServeHTTP(rec, req)
+
+	var doc openAPIDoc
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Len(t, doc.Paths, 5)
+
+	reserve := doc.Paths["/v1/reservations"]["post"]
+	assert.Equal(t, "ReserveProducts", reserve.OperationID)
+	assert.Equal(t, jsonSchema{"$ref": "#/components/schemas/ReserveProductsReq"}, reserve.RequestBody.Content["application/json"].Schema)
+	assert.Equal(t, jsonSchema{"$ref": "#/components/schemas/ReserveProductsResp"}, reserve.Responses["201"].Content["application/json"].Schema)
+	assert.Equal(t, jsonSchema{"$ref": "#/components/schemas/RestError"}, reserve.Responses["default"].Content["application/json"].Schema)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"$ref": "#/components/schemas/ReserveProductsResp"},
+		map[string]interface{}{"$ref": "#/components/schemas/RestError"},
+	}, reserve.Responses["422"].Content["application/json"].Schema["oneOf"])
+	assert.NotContains(t, doc.Paths["/v1/reservations/{id}"]["get"].Responses, "422")
+
+	products := doc.Paths["/v1/warehouses/{id}/products"]["get"]
+	assert.Equal(t, []openAPIParameter{{Name: "id", In: "path", Required: true, Schema: jsonSchema{"type": "integer"}}}, products.Parameters)
+	assert.Equal(t, jsonSchema{"type": "array", "items": map[string]interface{}{"$ref": "#/components/schemas/Product"}},
+		products.Responses["200"].Content["application/json"].Schema)
+
+	release := doc.Paths["/v1/reservations/{id}/items"]["delete"]
+	assert.Equal(t, []openAPIParameter{{Name: "id", In: "path", Required: true, Schema: jsonSchema{"type": "string"}}}, release.Parameters)
+	assert.Contains(t, doc.Components.Schemas, "ReleaseItemsReq")
+	assert.Contains(t, release.Responses, "404")
+}
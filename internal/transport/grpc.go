@@ -0,0 +1,234 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	productv1 "github.com/pintoter/warehouse-api/pkg/api/product/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var grpcCodes = map[model.ErrorCode]codes.Code{
+	model.CodeInvalidInput:      codes.InvalidArgument,
+	model.CodeNotFound:          codes.NotFound,
+	model.CodeInsufficientStock: codes.FailedPrecondition,
+	model.CodeConflict:          codes.Aborted,
+	model.CodeInternal:          codes.Internal,
+}
+
+// ProductServer serves productv1.ProductService with the same service.ProductService as the RPC handler
+type ProductServer struct {
+	productv1.UnimplementedProductServiceServer
+	productService service.ProductService
+}
+
+func NewProductServer(productService service.ProductService) *ProductServer {
+	return &ProductServer{productService: productService}
+}
+
+// Register adds the server to s
+func (p *ProductServer) Register(s *grpc.Server) {
+	productv1.RegisterProductServiceServer(s, p)
+}
+
+func (p *ProductServer) ReserveProducts(ctx context.Context, in *productv1.ReserveProductsRequest) (*productv1.ReserveProductsResponse, error) {
+	args := &model.ReserveProductsReq{
+		Atomic:            in.GetAtomic(),
+		TTLSeconds:        int(in.GetTtlSeconds()),
+		IdempotencyKey:    in.GetIdempotencyKey(),
+		AllowPartial:      in.GetAllowPartial(),
+		Strategy:          model.AllocationStrategyName(in.GetStrategy()),
+		WarehousePriority: toInts(in.GetWarehousePriority()),
+		DestinationRegion: in.GetDestinationRegion(),
+	}
+	if in.Destination != nil {
+		args.Destination = &model.GeoPoint{Latitude: in.Destination.GetLatitude(), Longitude: in.Destination.GetLongitude()}
+	}
+	for _, product := range in.GetProducts() {
+		args.Products = append(args.Products, model.ReserveProductReq{
+			Code:         product.GetCode(),
+			Quantity:     int(product.GetQuantity()),
+			WarehouseId:  int(product.GetWarehouseId()),
+			WarehouseIds: toInts(product.GetWarehouseIds()),
+		})
+	}
+
+	reply := new(model.ReserveProductsResp)
+	if err := p.productService.ReserveProducts(newGRPCRequest(ctx), args, reply); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	res := &productv1.ReserveProductsResponse{ReservationId: reply.ReservationId}
+	for _, line := range reply.ReservationProductsInfo {
+		result := &productv1.ReserveProductResult{
+			Code:      line.Code,
+			Status:    line.Status,
+			Requested: int32(line.Requested),
+			Reserved:  int32(line.Reserved),
+			Error:     toProtoError(line.Error),
+		}
+		if line.Available != nil {
+			available := int32(*line.Available)
+			result.Available = &available
+		}
+		res.Products = append(res.Products, result)
+	}
+
+	return res, nil
+}
+
+func (p *ProductServer) ReleaseProducts(ctx context.Context, in *productv1.ReleaseProductsRequest) (*productv1.ReleaseProductsResponse, error) {
+	args := &model.ReleaseProductsReq{IdempotencyKey: in.GetIdempotencyKey()}
+	for _, product := range in.GetProducts() {
+		args.Products = append(args.Products, model.ReleaseProductReq{
+			ReservationId: product.GetReservationId(),
+			Code:          product.GetCode(),
+			Quantity:      int(product.GetQuantity()),
+		})
+	}
+
+	reply := new(model.ReleaseProductsResp)
+	if err := p.productService.ReleaseProducts(newGRPCRequest(ctx), args, reply); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	res := new(productv1.ReleaseProductsResponse)
+	for _, line := range reply.ReleaseProductsInfo {
+		res.Products = append(res.Products, &productv1.ReleaseProductResult{
+			ReservationId: line.ReservationId,
+			Code:          line.Code,
+			Status:        line.Status,
+			Error:         toProtoError(line.Error),
+		})
+	}
+
+	return res, nil
+}
+
+func (p *ProductServer) CommitReservation(ctx context.Context, in *productv1.CommitReservationRequest) (*productv1.CommitReservationResponse, error) {
+	reply := new(model.CommitReservationResp)
+	err := p.productService.CommitReservation(newGRPCRequest(ctx), &model.CommitReservationReq{ReservationId: in.GetReservationId()}, reply)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &productv1.CommitReservationResponse{ReservationId: reply.ReservationId, Status: string(reply.Status)}, nil
+}
+
+func (p *ProductServer) CancelReservation(ctx context.Context, in *productv1.CancelReservationRequest) (*productv1.CancelReservationResponse, error) {
+	reply := new(model.CancelReservationResp)
+	err := p.productService.CancelReservation(newGRPCRequest(ctx), &model.CancelReservationReq{ReservationId: in.GetReservationId()}, reply)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	res := &productv1.CancelReservationResponse{ReservationId: reply.ReservationId, Status: string(reply.Status)}
+	for _, product := range reply.CancelledProductsInfo {
+		res.Products = append(res.Products, &productv1.CancelledProduct{
+			Code:        product.Code,
+			WarehouseId: int32(product.WarehouseId),
+			Quantity:    int32(product.Quantity),
+		})
+	}
+
+	return res, nil
+}
+
+func (p *ProductServer) GetReservation(ctx context.Context, in *productv1.GetReservationRequest) (*productv1.GetReservationResponse, error) {
+	reply := new(model.GetReservationResp)
+	err := p.productService.GetReservation(newGRPCRequest(ctx), &model.GetReservationReq{ReservationId: in.GetReservationId()}, reply)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	res := &productv1.GetReservationResponse{ReservationId: reply.ReservationId, Status: string(reply.Status)}
+	for _, line := range reply.Lines {
+		res.Lines = append(res.Lines, &productv1.ReservationLine{
+			Code:          line.Code,
+			Name:          line.Name,
+			Size:          line.Size,
+			WarehouseId:   int32(line.WarehouseId),
+			WarehouseName: line.WarehouseName,
+			Quantity:      int32(line.Quantity),
+			ReservedAt:    timestamppb.New(line.ReservedAt),
+			Status:        string(line.Status),
+		})
+	}
+
+	return res, nil
+}
+
+func (p *ProductServer) GetProductsByWarehouse(ctx context.Context, in *productv1.GetProductsByWarehouseRequest) (*productv1.GetProductsByWarehouseResponse, error) {
+	reply := new([]model.Product)
+	err := p.productService.GetProductsByWarehouse(newGRPCRequest(ctx), &model.ShowProductsReq{WarehouseId: int(in.GetWarehouseId())}, reply)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	res := new(productv1.GetProductsByWarehouseResponse)
+	for _, product := range *reply {
+		res.Products = append(res.Products, &productv1.Product{
+			Id:       int32(product.ID),
+			Name:     product.Name,
+			Size:     product.Size,
+			Code:     product.Code,
+			Quantity: int32(product.Quantity),
+		})
+	}
+
+	return res, nil
+}
+
+// newGRPCRequest carries the call context to the service methods, which take it from the HTTP request
+func newGRPCRequest(ctx context.Context) *http.Request {
+	return (&http.Request{Header: make(http.Header)}).WithContext(ctx)
+}
+
+// toGRPCError converts err to a status with the code derived from the model error and the error in details
+func toGRPCError(err error) error {
+	modelErr := model.NewError(err)
+	st := status.New(grpcCodes[modelErr.Code], modelErr.Message)
+	if withDetails, err := st.WithDetails(toProtoError(modelErr)); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+func toProtoError(modelErr *model.Error) *productv1.Error {
+	if modelErr == nil {
+		return nil
+	}
+
+	res := &productv1.Error{Code: string(modelErr.Code), Message: modelErr.Message}
+	if len(modelErr.Details) > 0 {
+		// Details may hold structs, e.g. violations, so they are converted through their JSON form
+		details := new(structpb.Struct)
+		if data, err := json.Marshal(modelErr.Details); err == nil && protojson.Unmarshal(data, details) == nil {
+			res.Details = details
+		}
+	}
+
+	return res
+}
+
+func toInts(values []int32) []int {
+	if len(values) == 0 {
+		return nil
+	}
+
+	res := make([]int, 0, len(values))
+	for _, v := range values {
+		res = append(res, int(v))
+	}
+
+	return res
+}
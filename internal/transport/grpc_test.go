@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	productv1 "github.com/pintoter/warehouse-api/pkg/api/product/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestProductServer(t *testing.T) {
+	server := NewProductServer(productServiceStub{})
+	ctx := context.Background()
+
+	t.Run("Reserve products", func(t *testing.T) {
+		res, err := server.ReserveProducts(ctx, &productv1.ReserveProductsRequest{
+			Products: []*productv1.ReserveProductLine{{Code: "1234", Quantity: 2}},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(&productv1.ReserveProductsResponse{
+			ReservationId: "a1",
+			Products:      []*productv1.ReserveProductResult{{Code: "1234", Status: "reserved", Requested: 2, Reserved: 2}},
+		}, res))
+	})
+
+	t.Run("Release products", func(t *testing.T) {
+		res, err := server.ReleaseProducts(ctx, &productv1.ReleaseProductsRequest{
+			Products: []*productv1.ReleaseProductLine{{ReservationId: "a1", Code: "1234", Quantity: 1}},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(&productv1.ReleaseProductsResponse{
+			Products: []*productv1.ReleaseProductResult{{ReservationId: "a1", Code: "1234", Status: "released"}},
+		}, res))
+	})
+
+	t.Run("Products by warehouse", func(t *testing.T) {
+		res, err := server.GetProductsByWarehouse(ctx, &productv1.GetProductsByWarehouseRequest{WarehouseId: 1})
+
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(&productv1.GetProductsByWarehouseResponse{
+			Products: []*productv1.Product{{Id: 1, Name: "Adidas", Size: "L", Code: "1234", Quantity: 3}},
+		}, res))
+	})
+
+	errTests := []struct {
+		name        string
+		call        func() error
+		wantCode    codes.Code
+		wantDetails *productv1.Error
+	}{
+		{
+			name: "Invalid input",
+			call: func() error {
+				_, err := server.ReserveProducts(ctx, &productv1.ReserveProductsRequest{})
+				return err
+			},
+			wantCode:    codes.InvalidArgument,
+			wantDetails: &productv1.Error{Code: "INVALID_INPUT", Message: "invalid input params"},
+		},
+		{
+			name: "Not found",
+			call: func() error {
+				_, err := server.GetReservation(ctx, &productv1.GetReservationRequest{ReservationId: "b2"})
+				return err
+			},
+			wantCode:    codes.NotFound,
+			wantDetails: &productv1.Error{Code: "NOT_FOUND", Message: "reservation not found"},
+		},
+		{
+			name: "Conflict",
+			call: func() error {
+				_, err := server.CommitReservation(ctx, &productv1.CommitReservationRequest{ReservationId: "a1"})
+				return err
+			},
+			wantCode:    codes.Aborted,
+			wantDetails: &productv1.Error{Code: "CONFLICT", Message: "reservation is already committed"},
+		},
+		{
+			name: "Insufficient stock with details",
+			call: func() error {
+				_, err := server.GetProductsByWarehouse(ctx, &productv1.GetProductsByWarehouseRequest{WarehouseId: 2})
+				return err
+			},
+			wantCode: codes.FailedPrecondition,
+			wantDetails: &productv1.Error{
+				Code:    "INSUFFICIENT_STOCK",
+				Message: "required quantity of products is missing",
+				Details: &structpb.Struct{Fields: map[string]*structpb.Value{"available": structpb.NewNumberValue(0)}},
+			},
+		},
+		{
+			name: "Validation with violations",
+			call: func() error {
+				return toGRPCError(model.Violations{{Field: "products[0].quantity", Reason: "must be positive"}}.Err())
+			},
+			wantCode: codes.InvalidArgument,
+			wantDetails: &productv1.Error{
+				Code:    "INVALID_INPUT",
+				Message: "request has invalid fields",
+				Details: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"violations": structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{
+						structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+							"field":  structpb.NewStringValue("products[0].quantity"),
+							"reason": structpb.NewStringValue("must be positive"),
+						}}),
+					}}),
+				}},
+			},
+		},
+	}
+
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(tt.call())
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.wantDetails.Message, st.Message())
+			if assert.Len(t, st.Details(), 1) {
+				assert.True(t, proto.Equal(tt.wantDetails, st.Details()[0].(*productv1.Error)))
+			}
+		})
+	}
+}
@@ -4,27 +4,37 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
-	"github.com/gorilla/rpc"
-	"github.com/gorilla/rpc/json"
+	"github.com/gorilla/rpc/v2"
 	"github.com/pintoter/warehouse-api/internal/service"
 	"github.com/pintoter/warehouse-api/pkg/logger"
 )
 
 type Handler struct {
-	router  *mux.Router
-	service service.ProductService
+	router           *mux.Router
+	productService   service.ProductService
+	warehouseService service.WarehouseService
+	catalogService   service.CatalogService
+	inventoryService service.InventoryService
 }
 
-func NewHandler(service service.ProductService) *Handler {
+func NewHandler(productService service.ProductService, warehouseService service.WarehouseService, catalogService service.CatalogService, inventoryService service.InventoryService) *Handler {
 	handler := &Handler{
-		router:  mux.NewRouter(),
-		service: service,
+		router:           mux.NewRouter(),
+		productService:   productService,
+		warehouseService: warehouseService,
+		catalogService:   catalogService,
+		inventoryService: inventoryService,
 	}
 
 	rpcServer := rpc.NewServer()
-	rpcServer.RegisterCodec(json.NewCodec(), "application/json")
-	_ = rpcServer.RegisterService(service, "ProductService")
-	handler.router.Handle("/rpc", rpcServer)
+	_ = rpcServer.RegisterService(productService, "ProductService")
+	_ = rpcServer.RegisterService(warehouseService, "WarehouseService")
+	_ = rpcServer.RegisterService(catalogService, "CatalogService")
+	_ = rpcServer.RegisterService(inventoryService, "InventoryService")
+	_ = rpcServer.RegisterService(newDiscoveryService(), "rpc")
+	handler.router.Handle("/rpc", newRPCHandler(rpcServer))
+	handler.initRESTRoutes()
+	handler.router.HandleFunc("/openapi.json", openAPIHandler()).Methods(http.MethodGet)
 
 	return handler
 }
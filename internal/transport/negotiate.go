@@ -0,0 +1,157 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/rpc/v2"
+)
+
+const (
+	contentTypeJSON    = "application/json"
+	contentTypeJSONRPC = "application/json-rpc"
+
+	maxBodySize       = 1 << 20
+	maxRPCBatchLength = 50
+)
+
+// rpcHandler picks the codec for a request: JSON-RPC 2.0 for the application/json-rpc
+// content type and for application/json bodies with the "jsonrpc" member or a batch array,
+// JSON-RPC 1.0 for the rest, so the old clients keep working
+type rpcHandler struct {
+	server *rpc.Server
+}
+
+func newRPCHandler(server *rpc.Server) *rpcHandler {
+	server.RegisterCodec(newJSONCodec(), contentTypeJSON)
+	server.RegisterCodec(newJSON2Codec(), contentTypeJSONRPC)
+
+	return &rpcHandler{server: server}
+}
+
+func (h *rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0]))
+	if contentType == "" {
+		contentType = contentTypeJSON
+	}
+
+	if r.Method != http.MethodPost || (contentType != contentTypeJSON && contentType != contentTypeJSONRPC) {
+		// gorilla reports the wrong method and content type itself
+		h.server.ServeHTTP(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		rpc.WriteError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("rpc: request body is limited to %d bytes", maxBodySize))
+		return
+	case err != nil:
+		rpc.WriteError(w, http.StatusBadRequest, "rpc: failed to read request body")
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	switch {
+	case len(body) > 0 && body[0] == '[':
+		h.serveBatch(w, r, body)
+	case contentType == contentTypeJSONRPC || isJSON2(body):
+		h.serve(w, r, body, contentTypeJSONRPC)
+	default:
+		h.serve(w, r, body, contentTypeJSON)
+	}
+}
+
+// isJSON2 reports whether the body is a JSON-RPC 2.0 request
+func isJSON2(body []byte) bool {
+	var req struct {
+		Version string `json:"jsonrpc"`
+	}
+	_ = json.Unmarshal(body, &req)
+
+	return req.Version != ""
+}
+
+func (h *rpcHandler) serve(w http.ResponseWriter, r *http.Request, body []byte, contentType string) {
+	req := r.Clone(r.Context())
+	req.Header.Set("Content-Type", contentType)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	h.server.ServeHTTP(w, req)
+}
+
+// serveBatch calls the requests of a JSON-RPC 2.0 batch one by one and writes the responses
+// as an array, notifications have no entry in it
+func (h *rpcHandler) serveBatch(w http.ResponseWriter, r *http.Request, body []byte) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		h.serve(w, r, body, contentTypeJSONRPC)
+		return
+	}
+
+	switch {
+	case len(batch) == 0:
+		writeBatchError(w, "rpc: empty batch")
+		return
+	case len(batch) > maxRPCBatchLength:
+		writeBatchError(w, fmt.Sprintf("rpc: batch is limited to %d requests", maxRPCBatchLength))
+		return
+	}
+
+	responses := make([]json.RawMessage, 0, len(batch))
+	for _, item := range batch {
+		rec := newResponseRecorder()
+		h.serve(rec, r, item, contentTypeJSONRPC)
+
+		if res := bytes.TrimSpace(rec.body.Bytes()); len(res) > 0 {
+			responses = append(responses, res)
+		}
+	}
+
+	if len(responses) == 0 {
+		// The batch had only notifications
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(responses)
+}
+
+// writeBatchError rejects the whole batch with an invalid request error
+func writeBatchError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(&json2Response{
+		Version: jsonRPCVersion,
+		Error:   &jsonError{Code: codeInvalidRequest, Message: message},
+		Id:      null,
+	})
+}
+
+// responseRecorder keeps the response of a single batch request
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header)}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(int) {}
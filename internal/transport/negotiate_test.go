@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCHandler(t *testing.T) {
+	server := rpc.NewServer()
+	err := server.RegisterService(EchoService{}, "Echo")
+	assert.NoError(t, err)
+
+	handler := newRPCHandler(server)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:        "JSON-RPC 1.0",
+			contentType: "application/json",
+			body:        `{"method":"Echo.Echo","params":[{"value":"hi"}],"id":1}`,
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"result":{"value":"hi"},"error":null,"id":1}`,
+		},
+		{
+			name:        "JSON-RPC 1.0 without content type",
+			contentType: "",
+			body:        `{"method":"Echo.Echo","params":[{"value":"hi"}],"id":1}`,
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"result":{"value":"hi"},"error":null,"id":1}`,
+		},
+		{
+			name:        "JSON-RPC 2.0 by body",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"hi"},"id":1}`,
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"jsonrpc":"2.0","result":{"value":"hi"},"id":1}`,
+		},
+		{
+			name:        "JSON-RPC 2.0 by content type",
+			contentType: "application/json-rpc",
+			body:        `{"method":"Echo.Echo","params":{"value":"hi"},"id":1}`,
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"jsonrpc":"2.0","error":{"code":-32600,"message":"rpc: jsonrpc must be \"2.0\""},"id":1}`,
+		},
+		{
+			name:        "Batch",
+			contentType: "application/json",
+			body: `[
+				{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"hi"},"id":1},
+				{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"bye"}},
+				{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"short"},"id":2},
+				{"jsonrpc":"2.0","method":"Echo.Shout","id":3},
+				1
+			]`,
+			wantStatus: http.StatusOK,
+			wantBody: `[
+				{"jsonrpc":"2.0","result":{"value":"hi"},"id":1},
+				{"jsonrpc":"2.0","error":{"code":-32002,"message":"required quantity of products is missing","data":{"code":"INSUFFICIENT_STOCK","message":"required quantity of products is missing","details":{"available":2}}},"id":2},
+				{"jsonrpc":"2.0","error":{"code":-32601,"message":"rpc: can't find method \"Echo.Shout\""},"id":3},
+				{"jsonrpc":"2.0","error":{"code":-32600,"message":"json: cannot unmarshal number into Go value of type transport.json2Request"},"id":null}
+			]`,
+		},
+		{
+			name:        "Batch of notifications",
+			contentType: "application/json",
+			body:        `[{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"hi"}}]`,
+			wantStatus:  http.StatusOK,
+			wantBody:    ``,
+		},
+		{
+			name:        "Empty batch",
+			contentType: "application/json",
+			body:        `[]`,
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"jsonrpc":"2.0","error":{"code":-32600,"message":"rpc: empty batch"},"id":null}`,
+		},
+		{
+			name:        "Batch over the limit",
+			contentType: "application/json",
+			body:        "[" + strings.Repeat(`{"jsonrpc":"2.0","method":"Echo.Echo","params":{"value":"hi"},"id":1},`, maxRPCBatchLength) + "1]",
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"jsonrpc":"2.0","error":{"code":-32600,"message":"rpc: batch is limited to 50 requests"},"id":null}`,
+		},
+		{
+			name:        "Body over the limit",
+			contentType: "application/json",
+			body:        `{"method":"Echo.Echo","params":[{"value":"` + strings.Repeat("a", maxBodySize) + `"}],"id":1}`,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "Malformed batch",
+			contentType: "application/json",
+			body:        `[{"jsonrpc":"2.0"`,
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"jsonrpc":"2.0","error":{"code":-32700,"message":"unexpected EOF"},"id":null}`,
+		},
+		{
+			name:        "Unsupported content type",
+			contentType: "text/plain",
+			body:        `{}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if strings.HasPrefix(tt.wantBody, "[") || strings.HasPrefix(tt.wantBody, "{") {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			} else if tt.wantStatus == http.StatusOK {
+				assert.Empty(t, rec.Body.String())
+			}
+		})
+	}
+}
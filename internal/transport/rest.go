@@ -0,0 +1,213 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+)
+
+var httpStatuses = map[model.ErrorCode]int{
+	model.CodeInvalidInput:      http.StatusBadRequest,
+	model.CodeNotFound:          http.StatusNotFound,
+	model.CodeInsufficientStock: http.StatusUnprocessableEntity,
+	model.CodeConflict:          http.StatusConflict,
+	model.CodeInternal:          http.StatusInternalServerError,
+}
+
+type restError struct {
+	Error *model.Error `json:"error"`
+}
+
+// releaseItemsReq is the body of DELETE /v1/reservations/{id}/items, the reservation comes from the path
+type releaseItemsReq struct {
+	Products []struct {
+		Code     string `json:"code"`
+		Quantity int    `json:"quantity"`
+	} `json:"products"`
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+// restRoute describes a REST route, the request and reply types are published in the OpenAPI document.
+// A route with line results also replies with the error statuses when every line is rejected.
+type restRoute struct {
+	method      string
+	path        string
+	operation   string
+	handler     func(h *Handler, w http.ResponseWriter, r *http.Request)
+	request     interface{}
+	reply       interface{}
+	status      int
+	lineResults bool
+}
+
+var restRoutes = []restRoute{
+	{http.MethodPost, "/v1/reservations", "ReserveProducts", (*Handler).reserveProducts, model.ReserveProductsReq{}, model.ReserveProductsResp{}, http.StatusCreated, true},
+	{http.MethodGet, "/v1/reservations/{id}", "GetReservation", (*Handler).getReservation, nil, model.GetReservationResp{}, http.StatusOK, false},
+	{http.MethodDelete, "/v1/reservations/{id}", "CancelReservation", (*Handler).cancelReservation, nil, model.CancelReservationResp{}, http.StatusOK, false},
+	{http.MethodPost, "/v1/reservations/{id}/commit", "CommitReservation", (*Handler).commitReservation, nil, model.CommitReservationResp{}, http.StatusOK, false},
+	{http.MethodDelete, "/v1/reservations/{id}/items", "ReleaseProducts", (*Handler).releaseProducts, releaseItemsReq{}, model.ReleaseProductsResp{}, http.StatusOK, true},
+	{http.MethodGet, "/v1/warehouses/{id:[0-9]+}/products", "GetProductsByWarehouse", (*Handler).getProductsByWarehouse, nil, []model.Product{}, http.StatusOK, false},
+}
+
+func (h *Handler) initRESTRoutes() {
+	for _, route := range restRoutes {
+		handler := route.handler
+		h.router.HandleFunc(route.path, func(w http.ResponseWriter, r *http.Request) {
+			handler(h, w, r)
+		}).Methods(route.method)
+	}
+}
+
+func (h *Handler) reserveProducts(w http.ResponseWriter, r *http.Request) {
+	args := new(model.ReserveProductsReq)
+	if err := decodeJSON(w, r, args); err != nil {
+		writeRESTError(w, err)
+		return
+	}
+
+	reply := new(model.ReserveProductsResp)
+	if err := h.productService.ReserveProducts(r, args, reply); err != nil {
+		writeRESTError(w, err)
+		return
+	}
+
+	writeJSON(w, reserveStatus(reply), reply)
+}
+
+// reserveStatus is 201 if any line is reserved, otherwise the status of the first line error
+func reserveStatus(reply *model.ReserveProductsResp) int {
+	lineErrs := make([]*model.Error, 0, len(reply.ReservationProductsInfo))
+	for _, line := range reply.ReservationProductsInfo {
+		lineErrs = append(lineErrs, line.Error)
+	}
+
+	return linesStatus(http.StatusCreated, lineErrs)
+}
+
+// linesStatus is status if any line succeeded, otherwise the status of the first line error
+func linesStatus(status int, lineErrs []*model.Error) int {
+	for _, lineErr := range lineErrs {
+		if lineErr == nil {
+			return status
+		}
+	}
+
+	if len(lineErrs) > 0 {
+		return httpStatuses[lineErrs[0].Code]
+	}
+	return status
+}
+
+func (h *Handler) getReservation(w http.ResponseWriter, r *http.Request) {
+	reply := new(model.GetReservationResp)
+	err := h.productService.GetReservation(r, &model.GetReservationReq{ReservationId: mux.Vars(r)["id"]}, reply)
+	if err != nil {
+		writeRESTError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, reply)
+}
+
+func (h *Handler) cancelReservation(w http.ResponseWriter, r *http.Request) {
+	reply := new(model.CancelReservationResp)
+	err := h.productService.CancelReservation(r, &model.CancelReservationReq{ReservationId: mux.Vars(r)["id"]}, reply)
+	if err != nil {
+		writeRESTError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, reply)
+}
+
+func (h *Handler) commitReservation(w http.ResponseWriter, r *http.Request) {
+	reply := new(model.CommitReservationResp)
+	err := h.productService.CommitReservation(r, &model.CommitReservationReq{ReservationId: mux.Vars(r)["id"]}, reply)
+	if err != nil {
+		writeRESTError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, reply)
+}
+
+func (h *Handler) releaseProducts(w http.ResponseWriter, r *http.Request) {
+	body := new(releaseItemsReq)
+	if err := decodeJSON(w, r, body); err != nil {
+		writeRESTError(w, err)
+		return
+	}
+
+	args := &model.ReleaseProductsReq{IdempotencyKey: body.IdempotencyKey}
+	for _, product := range body.Products {
+		args.Products = append(args.Products, model.ReleaseProductReq{
+			ReservationId: mux.Vars(r)["id"],
+			Code:          product.Code,
+			Quantity:      product.Quantity,
+		})
+	}
+
+	reply := new(model.ReleaseProductsResp)
+	if err := h.productService.ReleaseProducts(r, args, reply); err != nil {
+		writeRESTError(w, err)
+		return
+	}
+
+	writeJSON(w, releaseStatus(reply), reply)
+}
+
+// releaseStatus is 200 if any line is released, otherwise the status of the first line error
+func releaseStatus(reply *model.ReleaseProductsResp) int {
+	lineErrs := make([]*model.Error, 0, len(reply.ReleaseProductsInfo))
+	for _, line := range reply.ReleaseProductsInfo {
+		lineErrs = append(lineErrs, line.Error)
+	}
+
+	return linesStatus(http.StatusOK, lineErrs)
+}
+
+func (h *Handler) getProductsByWarehouse(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeRESTError(w, fmt.Errorf("%w: %s", model.ErrInvalidInput, err))
+		return
+	}
+
+	reply := new([]model.Product)
+	if err := h.productService.GetProductsByWarehouse(r, &model.ShowProductsReq{WarehouseId: id}, reply); err != nil {
+		writeRESTError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, reply)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(v)
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: request body is limited to %d bytes", model.ErrInvalidInput, maxBodySize)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", model.ErrInvalidInput, err)
+	}
+
+	return nil
+}
+
+func writeRESTError(w http.ResponseWriter, err error) {
+	modelErr := model.NewError(err)
+	writeJSON(w, httpStatuses[modelErr.Code], &restError{Error: modelErr})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,211 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/warehouse-api/internal/service"
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type productServiceStub struct {
+	service.ProductService
+}
+
+func (productServiceStub) ReserveProducts(_ *http.Request, args *model.ReserveProductsReq, reply *model.ReserveProductsResp) error {
+	if len(args.Products) == 0 {
+		return model.ErrInvalidInput
+	}
+
+	product := args.Products[0]
+	if product.Quantity > 10 {
+		*reply = model.ReserveProductsResp{ReservationId: "a1", ReservationProductsInfo: []model.ReserveProductResp{
+			{Code: product.Code, Status: "rejected: " + model.ErrInvalidQuantity.Error(), Requested: product.Quantity, Error: model.NewError(model.ErrInvalidQuantity)},
+		}}
+		return nil
+	}
+
+	*reply = model.ReserveProductsResp{ReservationId: "a1", ReservationProductsInfo: []model.ReserveProductResp{
+		{Code: product.Code, Status: "reserved", Requested: product.Quantity, Reserved: product.Quantity},
+	}}
+	return nil
+}
+
+func (productServiceStub) ReleaseProducts(_ *http.Request, args *model.ReleaseProductsReq, reply *model.ReleaseProductsResp) error {
+	*reply = model.ReleaseProductsResp{}
+	for _, product := range args.Products {
+		if product.ReservationId != "a1" {
+			reply.ReleaseProductsInfo = append(reply.ReleaseProductsInfo, model.ReleaseProductResp{
+				ReservationId: product.ReservationId,
+				Code:          product.Code,
+				Status:        "rejected: " + model.ErrReservationNotFound.Error(),
+				Error:         model.NewError(model.ErrReservationNotFound),
+			})
+			continue
+		}
+
+		reply.ReleaseProductsInfo = append(reply.ReleaseProductsInfo, model.ReleaseProductResp{
+			ReservationId: product.ReservationId,
+			Code:          product.Code,
+			Status:        "released",
+		})
+	}
+	return nil
+}
+
+func (productServiceStub) CommitReservation(_ *http.Request, args *model.CommitReservationReq, _ *model.CommitReservationResp) error {
+	return model.ErrReservationCommitted
+}
+
+func (productServiceStub) CancelReservation(_ *http.Request, _ *model.CancelReservationReq, _ *model.CancelReservationResp) error {
+	return model.ErrInternalServer
+}
+
+func (productServiceStub) GetReservation(_ *http.Request, args *model.GetReservationReq, reply *model.GetReservationResp) error {
+	if args.ReservationId != "a1" {
+		return model.ErrReservationNotFound
+	}
+
+	*reply = model.GetReservationResp{ReservationId: args.ReservationId, Status: model.ReservationReserved}
+	return nil
+}
+
+func (productServiceStub) GetProductsByWarehouse(_ *http.Request, args *model.ShowProductsReq, reply *[]model.Product) error {
+	if args.WarehouseId == 2 {
+		return model.WithDetails(model.ErrInvalidQuantity, map[string]interface{}{"available": 0})
+	}
+
+	*reply = []model.Product{{ID: 1, Name: "Adidas", Size: "L", Code: "1234", Quantity: 3}}
+	return nil
+}
+
+func TestREST(t *testing.T) {
+	handler := &Handler{router: mux.NewRouter(), productService: productServiceStub{}}
+	handler.initRESTRoutes()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Reserve products",
+			method:     http.MethodPost,
+			target:     "/v1/reservations",
+			body:       `{"products":[{"code":"1234","quantity":2}]}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"reservation_id":"a1","reservation_products_info":[{"code":"1234","status":"reserved","requested":2,"reserved":2}]}`,
+		},
+		{
+			name:       "Reserve products with every line rejected",
+			method:     http.MethodPost,
+			target:     "/v1/reservations",
+			body:       `{"products":[{"code":"1234","quantity":20}]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: `{"reservation_id":"a1","reservation_products_info":[{"code":"1234","status":"rejected: required quantity of products is missing",` +
+				`"requested":20,"reserved":0,"error":{"code":"INSUFFICIENT_STOCK","message":"required quantity of products is missing"}}]}`,
+		},
+		{
+			name:       "Reserve products with malformed body",
+			method:     http.MethodPost,
+			target:     "/v1/reservations",
+			body:       `{"products":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"INVALID_INPUT","message":"invalid input params: unexpected EOF"}}`,
+		},
+		{
+			name:       "Reserve products with invalid input",
+			method:     http.MethodPost,
+			target:     "/v1/reservations",
+			body:       `{"products":[]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"INVALID_INPUT","message":"invalid input params"}}`,
+		},
+		{
+			name:       "Get reservation",
+			method:     http.MethodGet,
+			target:     "/v1/reservations/a1",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"reservation_id":"a1","status":"reserved","lines":null}`,
+		},
+		{
+			name:       "Reservation not found",
+			method:     http.MethodGet,
+			target:     "/v1/reservations/b2",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":{"code":"NOT_FOUND","message":"reservation not found"}}`,
+		},
+		{
+			name:       "Commit committed reservation",
+			method:     http.MethodPost,
+			target:     "/v1/reservations/a1/commit",
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"error":{"code":"CONFLICT","message":"reservation is already committed"}}`,
+		},
+		{
+			name:       "Cancel reservation with internal error",
+			method:     http.MethodDelete,
+			target:     "/v1/reservations/a1",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":{"code":"INTERNAL","message":"internal server error, try later"}}`,
+		},
+		{
+			name:       "Release items",
+			method:     http.MethodDelete,
+			target:     "/v1/reservations/a1/items",
+			body:       `{"products":[{"code":"1234","quantity":1}]}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"release_products_info":[{"reservation_id":"a1","code":"1234","status":"released"}]}`,
+		},
+		{
+			name:       "Release items of unknown reservation",
+			method:     http.MethodDelete,
+			target:     "/v1/reservations/b2/items",
+			body:       `{"products":[{"code":"1234","quantity":1}]}`,
+			wantStatus: http.StatusNotFound,
+			wantBody: `{"release_products_info":[{"reservation_id":"b2","code":"1234","status":"rejected: reservation not found",` +
+				`"error":{"code":"NOT_FOUND","message":"reservation not found"}}]}`,
+		},
+		{
+			name:       "Release items with body over the limit",
+			method:     http.MethodDelete,
+			target:     "/v1/reservations/a1/items",
+			body:       `{"products":[],"idempotency_key":"` + strings.Repeat("k", maxBodySize) + `"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"INVALID_INPUT","message":"invalid input params: request body is limited to 1048576 bytes"}}`,
+		},
+		{
+			name:       "Products by warehouse",
+			method:     http.MethodGet,
+			target:     "/v1/warehouses/1/products",
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"id":1,"name":"Adidas","size":"L","code":"1234","quantity":3}]`,
+		},
+		{
+			name:       "Products by warehouse with insufficient stock",
+			method:     http.MethodGet,
+			target:     "/v1/warehouses/2/products",
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"error":{"code":"INSUFFICIENT_STOCK","message":"required quantity of products is missing","details":{"available":0}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type jsonSchema map[string]interface{}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaRegistry derives JSON schemas from Go types by their json tags,
+// named structs become components referenced with $ref
+type schemaRegistry struct {
+	schemas map[string]jsonSchema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: make(map[string]jsonSchema)}
+}
+
+func (s *schemaRegistry) schemaOf(t reflect.Type) jsonSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return jsonSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return jsonSchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return jsonSchema{"type": "number"}
+	case reflect.String:
+		return jsonSchema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return jsonSchema{"type": "array", "items": s.schemaOf(t.Elem())}
+	case reflect.Map:
+		return jsonSchema{"type": "object", "additionalProperties": s.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t == timeType {
+			return jsonSchema{"type": "string", "format": "date-time"}
+		}
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+
+		name := componentName(t)
+		if _, ok := s.schemas[name]; !ok {
+			// The placeholder stops the recursion on self-referencing types
+			s.schemas[name] = jsonSchema{}
+			s.schemas[name] = s.structSchema(t)
+		}
+		return jsonSchema{"$ref": "#/components/schemas/" + name}
+	default:
+		return jsonSchema{}
+	}
+}
+
+func (s *schemaRegistry) structSchema(t reflect.Type) jsonSchema {
+	properties := make(map[string]jsonSchema)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		properties[name] = s.schemaOf(field.Type)
+	}
+
+	return jsonSchema{"type": "object", "properties": properties}
+}
+
+// componentName is the type name with the first letter in upper case, unexported types are published too
+func componentName(t reflect.Type) string {
+	r, size := utf8.DecodeRuneInString(t.Name())
+	return string(unicode.ToUpper(r)) + t.Name()[size:]
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS reservation_expires_at_idx;
+
+ALTER TABLE reservation
+  DROP COLUMN IF EXISTS expired_at,
+  DROP COLUMN IF EXISTS expires_at;
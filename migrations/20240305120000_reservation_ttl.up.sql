@@ -0,0 +1,5 @@
+ALTER TABLE reservation
+  ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP,
+  ADD COLUMN IF NOT EXISTS expired_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS reservation_expires_at_idx ON reservation (expires_at) WHERE expired_at IS NULL;
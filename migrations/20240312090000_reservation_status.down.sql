@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS reservation_reservation_id_idx;
+
+DROP INDEX IF EXISTS reservation_expires_at_idx;
+
+CREATE INDEX IF NOT EXISTS reservation_expires_at_idx ON reservation (expires_at) WHERE expired_at IS NULL;
+
+ALTER TABLE reservation DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS RESERVATION_STATUS;
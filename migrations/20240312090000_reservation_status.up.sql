@@ -0,0 +1,14 @@
+CREATE TYPE RESERVATION_STATUS AS ENUM ('reserved', 'released', 'committed', 'expired');
+
+ALTER TABLE reservation
+  ADD COLUMN IF NOT EXISTS status RESERVATION_STATUS NOT NULL DEFAULT 'reserved';
+
+UPDATE reservation SET status = 'expired' WHERE expired_at IS NOT NULL;
+
+UPDATE reservation SET status = 'released' WHERE quantity = 0 AND status = 'reserved';
+
+DROP INDEX IF EXISTS reservation_expires_at_idx;
+
+CREATE INDEX IF NOT EXISTS reservation_expires_at_idx ON reservation (expires_at) WHERE status = 'reserved';
+
+CREATE INDEX IF NOT EXISTS reservation_reservation_id_idx ON reservation (reservation_id);
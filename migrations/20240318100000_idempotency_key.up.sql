@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS idempotency_key (
+  method VARCHAR(64) NOT NULL,
+  key VARCHAR(255) NOT NULL,
+  request_hash CHAR(64) NOT NULL,
+  response JSONB,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (method, key)
+);
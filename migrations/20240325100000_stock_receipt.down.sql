@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS receipt_line;
+DROP TABLE IF EXISTS receipt;
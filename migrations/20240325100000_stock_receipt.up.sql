@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS receipt (
+  id SERIAL PRIMARY KEY,
+  supplier_ref VARCHAR(64) NOT NULL,
+  warehouse_id INTEGER NOT NULL REFERENCES warehouse(id),
+  received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS receipt_line (
+  id SERIAL PRIMARY KEY,
+  receipt_id INTEGER NOT NULL REFERENCES receipt(id) ON DELETE CASCADE,
+  product_id INTEGER NOT NULL REFERENCES product(id),
+  quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS receipt_line_receipt_id_idx ON receipt_line (receipt_id);
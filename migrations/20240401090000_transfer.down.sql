@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS transfer;
+
+DROP TYPE IF EXISTS TRANSFER_STATUS;
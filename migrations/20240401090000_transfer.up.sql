@@ -0,0 +1,15 @@
+CREATE TYPE TRANSFER_STATUS AS ENUM ('shipped', 'received');
+
+CREATE TABLE IF NOT EXISTS transfer (
+  id SERIAL PRIMARY KEY,
+  product_id INTEGER NOT NULL REFERENCES product(id),
+  from_warehouse_id INTEGER NOT NULL REFERENCES warehouse(id),
+  to_warehouse_id INTEGER NOT NULL REFERENCES warehouse(id),
+  quantity INTEGER NOT NULL CHECK (quantity > 0),
+  status TRANSFER_STATUS NOT NULL DEFAULT 'shipped',
+  shipped_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  received_at TIMESTAMP,
+  CHECK (from_warehouse_id <> to_warehouse_id)
+);
+
+CREATE INDEX IF NOT EXISTS transfer_in_transit_idx ON transfer (to_warehouse_id) WHERE status = 'shipped';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS stock_movement;
+
+DROP FUNCTION IF EXISTS stock_movement_append_only();
+
+DROP TYPE IF EXISTS STOCK_MOVEMENT_REASON;
@@ -0,0 +1,34 @@
+CREATE TYPE STOCK_MOVEMENT_REASON AS ENUM (
+  'reserve',
+  'release',
+  'cancel',
+  'expire',
+  'receive',
+  'transfer_out',
+  'transfer_in',
+  'adjust'
+);
+
+CREATE TABLE IF NOT EXISTS stock_movement (
+  id BIGSERIAL PRIMARY KEY,
+  warehouse_id INTEGER NOT NULL REFERENCES warehouse(id),
+  product_id INTEGER NOT NULL REFERENCES product(id),
+  delta INTEGER NOT NULL CHECK (delta <> 0),
+  reason STOCK_MOVEMENT_REASON NOT NULL,
+  reference_id VARCHAR(64) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS stock_movement_product_id_created_at_idx ON stock_movement (product_id, created_at);
+
+CREATE INDEX IF NOT EXISTS stock_movement_warehouse_id_created_at_idx ON stock_movement (warehouse_id, created_at);
+
+CREATE OR REPLACE FUNCTION stock_movement_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'stock_movement is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movement_append_only
+  BEFORE UPDATE OR DELETE ON stock_movement
+  FOR EACH ROW EXECUTE FUNCTION stock_movement_append_only();
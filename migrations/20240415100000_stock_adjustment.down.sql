@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_adjustment;
+
+DROP TYPE IF EXISTS ADJUSTMENT_REASON;
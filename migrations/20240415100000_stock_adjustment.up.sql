@@ -0,0 +1,13 @@
+CREATE TYPE ADJUSTMENT_REASON AS ENUM ('damaged', 'lost', 'found', 'count');
+
+CREATE TABLE IF NOT EXISTS stock_adjustment (
+  id SERIAL PRIMARY KEY,
+  warehouse_id INTEGER NOT NULL REFERENCES warehouse(id),
+  product_id INTEGER NOT NULL REFERENCES product(id),
+  reason ADJUSTMENT_REASON NOT NULL,
+  counted_quantity INTEGER CHECK (counted_quantity >= 0),
+  delta INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS stock_adjustment_warehouse_id_product_id_idx ON stock_adjustment (warehouse_id, product_id);
@@ -0,0 +1,6 @@
+ALTER TABLE warehouse
+  DROP CONSTRAINT IF EXISTS warehouse_location_check,
+  DROP COLUMN IF EXISTS longitude,
+  DROP COLUMN IF EXISTS latitude,
+  DROP COLUMN IF EXISTS region,
+  DROP COLUMN IF EXISTS priority;
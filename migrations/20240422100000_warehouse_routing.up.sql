@@ -0,0 +1,6 @@
+ALTER TABLE warehouse
+  ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS region VARCHAR(32) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+  ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+  ADD CONSTRAINT warehouse_location_check CHECK ((latitude IS NULL) = (longitude IS NULL));
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 2 * time.Second
+)
+
+// ErrTransport wraps failures to deliver a call: network errors and 5xx responses without a JSON-RPC body
+var ErrTransport = errors.New("transport error")
+
+// Error is a JSON-RPC error returned by the server, Data holds the structured model error
+type Error struct {
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    *ErrorData `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// CodeOf returns the model error code of err, empty for errors not returned by the server methods
+func CodeOf(err error) ErrorCode {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) && rpcErr.Data != nil {
+		return rpcErr.Data.Code
+	}
+
+	return ""
+}
+
+// Client calls the warehouse API over JSON-RPC 2.0
+type Client struct {
+	url            string
+	httpClient     *http.Client
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	newKey         func() string
+	id             atomic.Uint64
+}
+
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client, http.DefaultClient is used by default
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times a call is repeated after transport errors and the exponential backoff bounds
+func WithRetries(maxRetries int, baseDelay, maxDelay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBaseDelay = baseDelay
+		c.retryMaxDelay = maxDelay
+	}
+}
+
+// WithIdempotencyKeys sets the generator of idempotency keys for calls made without one
+func WithIdempotencyKeys(newKey func() string) Option {
+	return func(c *Client) {
+		c.newKey = newKey
+	}
+}
+
+// New creates a client for the server at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		url:            strings.TrimSuffix(baseURL, "/") + "/rpc",
+		httpClient:     http.DefaultClient,
+		maxRetries:     defaultMaxRetries,
+		retryBaseDelay: defaultRetryBaseDelay,
+		retryMaxDelay:  defaultRetryMaxDelay,
+		newKey:         newIdempotencyKey,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func newIdempotencyKey() string {
+	return uuid.NewString()
+}
+
+type request struct {
+	Version string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	Id      uint64      `json:"id"`
+}
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// Call calls the method, e.g. "WarehouseService.ListWarehouses", and decodes the result into reply.
+// Calls failed with transport errors are repeated, so methods changing stock must carry an idempotency key.
+func (c *Client) Call(ctx context.Context, method string, args, reply interface{}) error {
+	body, err := json.Marshal(&request{Version: "2.0", Method: method, Params: args, Id: c.id.Add(1)})
+	if err != nil {
+		return err
+	}
+
+	delay := c.retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		err = c.call(ctx, body, reply)
+		if !errors.Is(err, ErrTransport) || attempt >= c.maxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay = min(2*delay, c.retryMaxDelay)
+	}
+}
+
+func (c *Client) call(ctx context.Context, body []byte, reply interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrTransport, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrTransport, err)
+	}
+
+	res := new(response)
+	if err := json.Unmarshal(data, res); err != nil {
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%w: %s", ErrTransport, resp.Status)
+		}
+		return fmt.Errorf("unexpected response %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	return json.Unmarshal(res.Result, reply)
+}
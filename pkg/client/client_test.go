@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedCall struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// newServer answers the calls with responses in order, a response without a body is written as 503
+func newServer(t *testing.T, responses ...string) (*httptest.Server, *[]recordedCall) {
+	var (
+		mu    sync.Mutex
+		calls []recordedCall
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		var call recordedCall
+		assert.NoError(t, json.Unmarshal(body, &call))
+		assert.Equal(t, "/rpc", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		res := responses[len(calls)]
+		calls = append(calls, call)
+		if res == "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(res))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestReserveProducts(t *testing.T) {
+	reply := `{"jsonrpc":"2.0","result":{"reservation_id":"a1","reservation_products_info":[{"code":"1234","status":"reserved","requested":2,"reserved":2}]},"id":1}`
+
+	tests := []struct {
+		name      string
+		responses []string
+		args      ReserveProductsReq
+		wantKey   string
+		wantCalls int
+		wantResp  *ReserveProductsResp
+		wantErr   error
+	}{
+		{
+			name:      "Success with generated key",
+			responses: []string{reply},
+			args:      ReserveProductsReq{Products: []ReserveProductReq{{Code: "1234", Quantity: 2}}},
+			wantKey:   "key-1",
+			wantCalls: 1,
+			wantResp: &ReserveProductsResp{ReservationId: "a1", ReservationProductsInfo: []ReserveProductResp{
+				{Code: "1234", Status: "reserved", Requested: 2, Reserved: 2},
+			}},
+		},
+		{
+			name:      "Retry keeps the key",
+			responses: []string{"", "", reply},
+			args:      ReserveProductsReq{Products: []ReserveProductReq{{Code: "1234", Quantity: 2}}, IdempotencyKey: "order-42"},
+			wantKey:   "order-42",
+			wantCalls: 3,
+			wantResp: &ReserveProductsResp{ReservationId: "a1", ReservationProductsInfo: []ReserveProductResp{
+				{Code: "1234", Status: "reserved", Requested: 2, Reserved: 2},
+			}},
+		},
+		{
+			name:      "Retries exhausted",
+			responses: []string{"", "", ""},
+			args:      ReserveProductsReq{Products: []ReserveProductReq{{Code: "1234", Quantity: 2}}},
+			wantKey:   "key-1",
+			wantCalls: 3,
+			wantErr:   ErrTransport,
+		},
+		{
+			name:      "Method error is not retried",
+			responses: []string{`{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid input params","data":{"code":"INVALID_INPUT","message":"invalid input params"}},"id":1}`},
+			args:      ReserveProductsReq{},
+			wantKey:   "key-1",
+			wantCalls: 1,
+			wantErr: &Error{Code: -32602, Message: "invalid input params", Data: &ErrorData{
+				Code:    CodeInvalidInput,
+				Message: "invalid input params",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newServer(t, tt.responses...)
+			c := New(server.URL,
+				WithRetries(2, time.Millisecond, time.Millisecond),
+				WithIdempotencyKeys(func() string { return "key-1" }),
+			)
+
+			gotResp, err := c.ReserveProducts(context.Background(), tt.args)
+			if tt.wantErr != nil {
+				if errors.Is(tt.wantErr, ErrTransport) {
+					assert.ErrorIs(t, err, ErrTransport)
+				} else {
+					assert.Equal(t, tt.wantErr, err)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantResp, gotResp)
+			}
+
+			assert.Len(t, *calls, tt.wantCalls)
+			for _, call := range *calls {
+				var params ReserveProductsReq
+				assert.NoError(t, json.Unmarshal(call.Params, &params))
+				assert.Equal(t, "2.0", call.Version)
+				assert.Equal(t, "ProductService.ReserveProducts", call.Method)
+				assert.Equal(t, tt.wantKey, params.IdempotencyKey)
+			}
+		})
+	}
+}
+
+func TestGetProductsByWarehouse(t *testing.T) {
+	server, calls := newServer(t,
+		`{"jsonrpc":"2.0","result":[{"id":1,"name":"Adidas","size":"L","code":"1234","quantity":3}],"id":1}`,
+		`{"jsonrpc":"2.0","error":{"code":-32001,"message":"warehouse not found","data":{"code":"NOT_FOUND","message":"warehouse not found"}},"id":2}`,
+	)
+	c := New(server.URL)
+
+	products, err := c.GetProductsByWarehouse(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []Product{{ID: 1, Name: "Adidas", Size: "L", Code: "1234", Quantity: 3}}, products)
+
+	_, err = c.GetProductsByWarehouse(context.Background(), 7)
+	assert.Equal(t, CodeNotFound, CodeOf(err))
+
+	assert.JSONEq(t, `{"warehouse_id":1}`, string((*calls)[0].Params))
+	assert.JSONEq(t, `{"warehouse_id":7}`, string((*calls)[1].Params))
+}
+
+func TestCallCanceled(t *testing.T) {
+	server, calls := newServer(t, "", "")
+	c := New(server.URL, WithRetries(1, time.Hour, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.CommitReservation(ctx, "a1")
+	assert.ErrorIs(t, err, ErrTransport)
+	assert.Len(t, *calls, 1)
+}
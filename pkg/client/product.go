@@ -0,0 +1,72 @@
+package client
+
+import "context"
+
+// ReserveProducts reserves products, a call without an idempotency key gets a generated one,
+// so a retried call can't reserve the products twice
+func (c *Client) ReserveProducts(ctx context.Context, args ReserveProductsReq) (*ReserveProductsResp, error) {
+	if args.IdempotencyKey == "" {
+		args.IdempotencyKey = c.newKey()
+	}
+
+	reply := new(ReserveProductsResp)
+	if err := c.Call(ctx, "ProductService.ReserveProducts", &args, reply); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// ReleaseProducts releases reserved products, a call without an idempotency key gets a generated one
+func (c *Client) ReleaseProducts(ctx context.Context, args ReleaseProductsReq) (*ReleaseProductsResp, error) {
+	if args.IdempotencyKey == "" {
+		args.IdempotencyKey = c.newKey()
+	}
+
+	reply := new(ReleaseProductsResp)
+	if err := c.Call(ctx, "ProductService.ReleaseProducts", &args, reply); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func (c *Client) CommitReservation(ctx context.Context, reservationId string) (*CommitReservationResp, error) {
+	reply := new(CommitReservationResp)
+	err := c.Call(ctx, "ProductService.CommitReservation", &CommitReservationReq{ReservationId: reservationId}, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func (c *Client) CancelReservation(ctx context.Context, reservationId string) (*CancelReservationResp, error) {
+	reply := new(CancelReservationResp)
+	err := c.Call(ctx, "ProductService.CancelReservation", &CancelReservationReq{ReservationId: reservationId}, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func (c *Client) GetReservation(ctx context.Context, reservationId string) (*GetReservationResp, error) {
+	reply := new(GetReservationResp)
+	err := c.Call(ctx, "ProductService.GetReservation", &GetReservationReq{ReservationId: reservationId}, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func (c *Client) GetProductsByWarehouse(ctx context.Context, warehouseId int) ([]Product, error) {
+	var reply []Product
+	err := c.Call(ctx, "ProductService.GetProductsByWarehouse", &ShowProductsReq{WarehouseId: warehouseId}, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
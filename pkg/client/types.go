@@ -0,0 +1,161 @@
+package client
+
+import "time"
+
+// ReservationStatus is the status of a reservation or of one of its lines
+type ReservationStatus string
+
+const (
+	ReservationReserved  ReservationStatus = "reserved"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationCommitted ReservationStatus = "committed"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// AllocationStrategyName chooses how a reservation is split between warehouses
+type AllocationStrategyName string
+
+const (
+	StrategyLargestStockFirst AllocationStrategyName = "largest_stock_first"
+	StrategyFewestWarehouses  AllocationStrategyName = "fewest_warehouses"
+	StrategyWarehousePriority AllocationStrategyName = "warehouse_priority"
+	StrategySingleWarehouse   AllocationStrategyName = "single_warehouse"
+	StrategyNearest           AllocationStrategyName = "nearest"
+)
+
+// ErrorCode is a stable machine-readable error class returned by the server
+type ErrorCode string
+
+const (
+	CodeInvalidInput      ErrorCode = "INVALID_INPUT"
+	CodeNotFound          ErrorCode = "NOT_FOUND"
+	CodeInsufficientStock ErrorCode = "INSUFFICIENT_STOCK"
+	CodeConflict          ErrorCode = "CONFLICT"
+	CodeInternal          ErrorCode = "INTERNAL"
+)
+
+// ErrorData is the structured error of a call or of a rejected line
+type ErrorData struct {
+	Code    ErrorCode              `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// GeoPoint is a position in degrees
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type ReserveProductReq struct {
+	Code         string `json:"code"`
+	Quantity     int    `json:"quantity"`
+	WarehouseId  int    `json:"warehouse_id,omitempty"`
+	WarehouseIds []int  `json:"warehouse_ids,omitempty"`
+}
+
+type ReserveProductsReq struct {
+	Products          []ReserveProductReq    `json:"products"`
+	Atomic            bool                   `json:"atomic"`
+	TTLSeconds        int                    `json:"ttl_seconds"`
+	IdempotencyKey    string                 `json:"idempotency_key"`
+	AllowPartial      bool                   `json:"allow_partial"`
+	Strategy          AllocationStrategyName `json:"strategy"`
+	WarehousePriority []int                  `json:"warehouse_priority"`
+	Destination       *GeoPoint              `json:"destination"`
+	DestinationRegion string                 `json:"destination_region"`
+}
+
+type ReserveProductResp struct {
+	Code      string     `json:"code"`
+	Status    string     `json:"status"`
+	Requested int        `json:"requested"`
+	Reserved  int        `json:"reserved"`
+	Available *int       `json:"available,omitempty"`
+	Error     *ErrorData `json:"error,omitempty"`
+}
+
+type ReserveProductsResp struct {
+	ReservationId           string               `json:"reservation_id"`
+	ReservationProductsInfo []ReserveProductResp `json:"reservation_products_info"`
+}
+
+type ReleaseProductReq struct {
+	ReservationId string `json:"reservation_id"`
+	Code          string `json:"code"`
+	Quantity      int    `json:"quantity"`
+}
+
+type ReleaseProductsReq struct {
+	Products       []ReleaseProductReq `json:"products"`
+	IdempotencyKey string              `json:"idempotency_key"`
+}
+
+type ReleaseProductResp struct {
+	ReservationId string     `json:"reservation_id"`
+	Code          string     `json:"code"`
+	Status        string     `json:"status"`
+	Error         *ErrorData `json:"error,omitempty"`
+}
+
+type ReleaseProductsResp struct {
+	ReleaseProductsInfo []ReleaseProductResp `json:"release_products_info"`
+}
+
+type CommitReservationReq struct {
+	ReservationId string `json:"reservation_id"`
+}
+
+type CommitReservationResp struct {
+	ReservationId string            `json:"reservation_id"`
+	Status        ReservationStatus `json:"status"`
+}
+
+type CancelReservationReq struct {
+	ReservationId string `json:"reservation_id"`
+}
+
+type CancelledProductResp struct {
+	Code        string `json:"code"`
+	WarehouseId int    `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+type CancelReservationResp struct {
+	ReservationId         string                 `json:"reservation_id"`
+	Status                ReservationStatus      `json:"status"`
+	CancelledProductsInfo []CancelledProductResp `json:"cancelled_products_info"`
+}
+
+type GetReservationReq struct {
+	ReservationId string `json:"reservation_id"`
+}
+
+type ReservationLine struct {
+	Code          string            `json:"code"`
+	Name          string            `json:"name"`
+	Size          string            `json:"size"`
+	WarehouseId   int               `json:"warehouse_id"`
+	WarehouseName string            `json:"warehouse_name"`
+	Quantity      int               `json:"quantity"`
+	ReservedAt    time.Time         `json:"reserved_at"`
+	Status        ReservationStatus `json:"status"`
+}
+
+type GetReservationResp struct {
+	ReservationId string            `json:"reservation_id"`
+	Status        ReservationStatus `json:"status"`
+	Lines         []ReservationLine `json:"lines"`
+}
+
+type ShowProductsReq struct {
+	WarehouseId int `json:"warehouse_id"`
+}
+
+type Product struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Size     string `json:"size"`
+	Code     string `json:"code"`
+	Quantity int    `json:"quantity"`
+}
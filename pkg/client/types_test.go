@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pintoter/warehouse-api/internal/service/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTypesMatchServer decodes server models into the client types, so a field missing on either side fails the test
+func TestTypesMatchServer(t *testing.T) {
+	available := 1
+	reservedAt := time.Date(2024, 4, 8, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		server interface{}
+		client interface{}
+	}{
+		{
+			name: "Reserve request",
+			server: model.ReserveProductsReq{
+				Products:          []model.ReserveProductReq{{Code: "12345", Quantity: 2, WarehouseId: 1, WarehouseIds: []int{1, 2}}},
+				Atomic:            true,
+				TTLSeconds:        60,
+				IdempotencyKey:    "key-1",
+				AllowPartial:      true,
+				Strategy:          model.StrategyNearest,
+				WarehousePriority: []int{2, 1},
+				Destination:       &model.GeoPoint{Latitude: 55.75, Longitude: 37.61},
+				DestinationRegion: "moscow",
+			},
+			client: new(ReserveProductsReq),
+		},
+		{
+			name: "Reserve response",
+			server: model.ReserveProductsResp{
+				ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+				ReservationProductsInfo: []model.ReserveProductResp{{
+					Code: "12345", Status: "rejected", Requested: 2, Available: &available,
+					Error: &model.Error{Code: model.CodeInsufficientStock, Message: "not enough", Details: map[string]interface{}{"available": 1.0}},
+				}},
+			},
+			client: new(ReserveProductsResp),
+		},
+		{
+			name: "Release request",
+			server: model.ReleaseProductsReq{
+				Products:       []model.ReleaseProductReq{{ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1", Code: "12345", Quantity: 1}},
+				IdempotencyKey: "key-1",
+			},
+			client: new(ReleaseProductsReq),
+		},
+		{
+			name: "Release response",
+			server: model.ReleaseProductsResp{ReleaseProductsInfo: []model.ReleaseProductResp{{
+				ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1", Code: "12345", Status: "rejected",
+				Error: &model.Error{Code: model.CodeNotFound, Message: "reservation not found"},
+			}}},
+			client: new(ReleaseProductsResp),
+		},
+		{
+			name:   "Commit response",
+			server: model.CommitReservationResp{ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1", Status: model.ReservationCommitted},
+			client: new(CommitReservationResp),
+		},
+		{
+			name: "Cancel response",
+			server: model.CancelReservationResp{
+				ReservationId:         "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+				Status:                model.ReservationReleased,
+				CancelledProductsInfo: []model.CancelledProductResp{{Code: "12345", WarehouseId: 1, Quantity: 2}},
+			},
+			client: new(CancelReservationResp),
+		},
+		{
+			name: "Reservation",
+			server: model.GetReservationResp{
+				ReservationId: "422ab5fa-fbf1-461a-99dc-2c6a49c323f1",
+				Status:        model.ReservationReserved,
+				Lines: []model.ReservationLine{{
+					Code: "12345", Name: "Puma Cap", Size: "M", WarehouseId: 1, WarehouseName: "Podolsk",
+					Quantity: 2, ReservedAt: reservedAt, Status: model.ReservationReserved,
+				}},
+			},
+			client: new(GetReservationResp),
+		},
+		{
+			name:   "Products",
+			server: []model.Product{{ID: 1, Name: "Puma Cap", Size: "M", Code: "12345", Quantity: 3}},
+			client: new([]Product),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverJSON, err := json.Marshal(tt.server)
+			assert.NoError(t, err)
+
+			dec := json.NewDecoder(bytes.NewReader(serverJSON))
+			dec.DisallowUnknownFields()
+			assert.NoError(t, dec.Decode(tt.client))
+
+			clientJSON, err := json.Marshal(tt.client)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(serverJSON), string(clientJSON))
+		})
+	}
+}